@@ -0,0 +1,16 @@
+package admin
+
+import (
+	"service/domain/order"
+)
+
+// Handler serves endpoints for operators of the service.
+type Handler struct {
+	breaches order.BreachFinder
+}
+
+func NewHandler(breaches order.BreachFinder) *Handler {
+	return &Handler{
+		breaches: breaches,
+	}
+}
@@ -0,0 +1,74 @@
+package admin
+
+import (
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"service/http/httpstatus"
+	"strconv"
+	"time"
+)
+
+const (
+	// DefaultBreachesLimit is how many breaches are listed if limit is not provided.
+	DefaultBreachesLimit = 100
+
+	// MaxBreachesLimit is the maximum number of breaches listed at once.
+	MaxBreachesLimit = 1000
+)
+
+type ListSLABreachesResponse struct {
+	Breaches []SLABreachResponse `json:"breaches"`
+}
+
+type SLABreachResponse struct { //nolint:govet
+	OrderID    uuid.UUID `json:"order_id"`
+	State      string    `json:"state"`
+	EnteredAt  time.Time `json:"entered_at"`
+	MaxDwell   string    `json:"max_dwell"`
+	Dwell      string    `json:"dwell"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// ListSLABreaches lists orders which are still stuck in the state they have breached SLA in.
+func (h *Handler) ListSLABreaches(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit := DefaultBreachesLimit
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > MaxBreachesLimit {
+			httpstatus.BadRequest(ctx, w, errors.Errorf("invalid limit: must be between 1 and %d", MaxBreachesLimit))
+			return
+		}
+
+		limit = parsed
+	}
+
+	breaches, err := h.breaches.FindOpenBreaches(ctx, limit)
+	if err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to find sla breaches"))
+		return
+	}
+
+	now := time.Now()
+
+	response := ListSLABreachesResponse{
+		Breaches: make([]SLABreachResponse, len(breaches)),
+	}
+
+	for i, breach := range breaches {
+		response.Breaches[i] = SLABreachResponse{
+			OrderID:    breach.OrderID,
+			State:      breach.State.String(),
+			EnteredAt:  breach.EnteredAt,
+			MaxDwell:   breach.MaxDwell.String(),
+			Dwell:      now.Sub(breach.EnteredAt).Round(time.Second).String(),
+			DetectedAt: breach.DetectedAt,
+		}
+	}
+
+	render.JSON(w, r, response)
+}
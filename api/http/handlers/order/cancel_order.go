@@ -0,0 +1,100 @@
+package order
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"service/domain/order"
+	"service/http/httpstatus"
+	"time"
+)
+
+// Canceler cancels order and tells other services about it.
+type Canceler interface {
+	Cancel(ctx context.Context, id uuid.UUID, reason string, actor order.Actor) (*order.Order, error)
+}
+
+type CancelOrderRequest struct {
+	Reason string `json:"reason"`
+}
+
+type CancelOrderResponse struct { //nolint:govet
+	OrderID      uuid.UUID            `json:"order_id"`
+	State        string               `json:"state"`
+	Cancellation CancellationResponse `json:"cancellation"`
+	Timestamp    time.Time            `json:"timestamp"`
+}
+
+// CancelOrder cancels order on behalf of the customer.
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	h.cancelOrder(w, r, "cancel order", order.ActorCustomer)
+}
+
+// CancelOrderBySupport cancels order on behalf of a support agent.
+// It must be served only behind a route protected by gateway.
+func (h *Handler) CancelOrderBySupport(w http.ResponseWriter, r *http.Request) {
+	h.cancelOrder(w, r, "cancel order by support", order.ActorSupport)
+}
+
+func (h *Handler) cancelOrder(w http.ResponseWriter, r *http.Request, spanName string, actor order.Actor) {
+	var (
+		ctx, span = h.tracer.Start(r.Context(), spanName)
+	)
+
+	defer span.End()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	cancelOrder := &CancelOrderRequest{}
+
+	err = render.DecodeJSON(r.Body, cancelOrder)
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	}
+
+	o, err := h.orderService.Cancel(ctx, id, cancelOrder.Reason, actor)
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrCancellationReasonTooLong):
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrCancellationForbidden):
+		httpstatus.Forbidden(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrOrderClosed),
+		errors.Is(err, order.ErrOrderCanceled),
+		errors.Is(err, order.ErrConcurrentModification):
+		httpstatus.Conflict(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to cancel order"))
+		return
+	}
+
+	span.AddEvent("canceled order")
+
+	cancellation := o.Cancellation()
+
+	response := CancelOrderResponse{
+		OrderID: o.ID(),
+		State:   o.State().String(),
+		Cancellation: CancellationResponse{
+			Reason:     cancellation.Reason(),
+			CanceledBy: cancellation.By().String(),
+			CanceledAt: cancellation.At(),
+		},
+		Timestamp: time.Now(),
+	}
+
+	httpstatus.Ok(w, response)
+}
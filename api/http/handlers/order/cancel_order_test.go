@@ -0,0 +1,99 @@
+package order_test
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	"net/http"
+	"net/http/httptest"
+	handlers "service/api/http/handlers/order"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	"strings"
+	"testing"
+)
+
+// inMemoryCanceler cancels orders kept in memory.
+type inMemoryCanceler struct {
+	handlers.OrderService
+	orders map[uuid.UUID]*order.Order
+}
+
+func (c *inMemoryCanceler) Cancel(_ context.Context, id uuid.UUID, reason string, actor order.Actor) (*order.Order, error) {
+	o, ok := c.orders[id]
+	if !ok {
+		return nil, order.ErrOrderNotFound
+	}
+
+	if _, err := order.NewStateOperator(o, order.WithActor(actor)).CancelOrder(reason); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+func deliveringOrder(t *testing.T) *order.Order {
+	t.Helper()
+
+	o := ordertest.NewOrder(t)
+	operator := order.NewStateOperator(o)
+
+	_, err := operator.PayOrder(uuid.New(), o.Total())
+	require.NoError(t, err)
+	_, err = operator.CookOrder()
+	require.NoError(t, err)
+	_, err = operator.OrderFinished()
+	require.NoError(t, err)
+	_, err = operator.WaitForCourier()
+	require.NoError(t, err)
+	_, err = operator.CourierTookOrder(uuid.New())
+	require.NoError(t, err)
+	_, err = operator.DeliveringOrder()
+	require.NoError(t, err)
+
+	return o
+}
+
+func TestHandler_CancelOrder(t *testing.T) {
+	customerCanceled, supportCanceled := deliveringOrder(t), deliveringOrder(t)
+
+	handler := handlers.NewHandler(
+		noop.NewTracerProvider().Tracer(""),
+		nil, nil,
+		&inMemoryCanceler{orders: map[uuid.UUID]*order.Order{
+			customerCanceled.ID(): customerCanceled,
+			supportCanceled.ID():  supportCanceled,
+		}},
+		nil, nil, nil, nil, nil, nil,
+	)
+
+	r := chi.NewRouter()
+	r.Post("/order/{uuid}/cancel", handler.CancelOrder)
+	r.Post("/admin/order/{uuid}/cancel", handler.CancelOrderBySupport)
+
+	cancel := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path,
+			strings.NewReader(`{"reason":"changed my mind","canceled_by":"support"}`)))
+
+		return w
+	}
+
+	t.Run("assert customer cannot cancel delivering order", func(t *testing.T) {
+		w := cancel("/order/" + customerCanceled.ID().String() + "/cancel")
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Equal(t, order.Delivering, customerCanceled.State())
+		assert.True(t, customerCanceled.Cancellation().IsZero())
+	})
+
+	t.Run("assert support can cancel delivering order", func(t *testing.T) {
+		w := cancel("/admin/order/" + supportCanceled.ID().String() + "/cancel")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, order.ActorSupport, supportCanceled.Cancellation().By())
+	})
+}
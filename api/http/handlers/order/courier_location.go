@@ -0,0 +1,278 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"service/domain/order"
+	"service/domain/shared/destination"
+	"service/http/httpstatus"
+	"strings"
+	"time"
+)
+
+const (
+	// locationsPingInterval is how often subscriber is pinged to detect lost connection.
+	locationsPingInterval = 15 * time.Second
+
+	// locationsPongWait is how long subscriber may not answer a ping before connection is closed.
+	locationsPongWait = 2 * locationsPingInterval
+
+	// locationsWriteWait is how long writing a message to subscriber may take.
+	locationsWriteWait = 10 * time.Second
+)
+
+type TrackCourierRequest struct { //nolint:govet
+	CourierID string  `json:"courier_id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+
+	// At is an optional time location has been taken at. It is the time request is received by default.
+	// Time ahead of now more than [order.MaxLocationClockSkew] is rejected.
+	At *time.Time `json:"at,omitempty"`
+}
+
+type CourierLocationResponse struct { //nolint:govet
+	OrderID   uuid.UUID `json:"order_id"`
+	CourierID uuid.UUID `json:"courier_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	At        time.Time `json:"at"`
+}
+
+// TrackCourier tracks location of the courier delivering the order.
+func (h *Handler) TrackCourier(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	trackCourier := &TrackCourierRequest{}
+
+	if err = render.DecodeJSON(r.Body, trackCourier); err != nil {
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	}
+
+	courierID, err := uuid.Parse(trackCourier.CourierID)
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid courier id"))
+		return
+	}
+
+	var at time.Time
+	if trackCourier.At != nil {
+		at = *trackCourier.At
+	}
+
+	o, err := h.repository.Get(ctx, id)
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get order"))
+		return
+	}
+
+	l, err := o.TrackCourier(courierID, trackCourier.Latitude, trackCourier.Longitude, at)
+	switch {
+	case errors.Is(err, destination.ErrInvalidLatitude),
+		errors.Is(err, destination.ErrInvalidLongitude),
+		errors.Is(err, order.ErrLocationInFuture):
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrCourierNotAssigned):
+		httpstatus.Forbidden(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrCourierNotTracked):
+		httpstatus.Conflict(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, err)
+		return
+	}
+
+	if err = h.locations.Track(ctx, l); err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to track courier"))
+		return
+	}
+
+	httpstatus.Ok(w, locationResponse(l))
+}
+
+// CourierLocations relays locations of the courier delivering the order to a WebSocket subscriber.
+// The latest known location is sent first. Connection is closed once the order is delivered
+// or the courier is no longer tracked otherwise, e.g. order is canceled, and when server shuts down.
+func (h *Handler) CourierLocations(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	// subscribed before order is read, so a change made in between is not missed
+	changes, err := h.changes.Subscribe(ctx, id)
+	if err != nil {
+		httpstatus.ServiceUnavailable(ctx, w, errors.Wrap(err, "failed to subscribe to order changes"))
+		return
+	}
+
+	locations, err := h.locations.Subscribe(ctx, id)
+	if err != nil {
+		httpstatus.ServiceUnavailable(ctx, w, errors.Wrap(err, "failed to subscribe to courier locations"))
+		return
+	}
+
+	o, err := h.repository.Get(ctx, id)
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get order"))
+		return
+	}
+
+	if !o.CourierTracked() {
+		httpstatus.Conflict(ctx, w, errors.Wrapf(order.ErrCourierNotTracked, "order is %s", o.State()))
+		return
+	}
+
+	latest, err := h.locations.Latest(ctx, id)
+	found := err == nil
+
+	if err != nil && !errors.Is(err, order.ErrLocationNotFound) {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get courier location"))
+		return
+	}
+
+	// upgrader responds with an error itself
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	go readControl(conn, cancel)
+
+	if found {
+		if err = writeLocation(conn, latest); err != nil {
+			return
+		}
+	}
+
+	ping := time.NewTicker(locationsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case l, ok := <-locations:
+			if !ok {
+				return
+			}
+
+			if err = writeLocation(conn, l); err != nil {
+				return
+			}
+		case _, ok := <-changes:
+			if !ok {
+				closeConn(conn, websocket.CloseGoingAway, "server is shutting down")
+				return
+			}
+
+			if o, err = h.repository.Get(ctx, id); err != nil {
+				closeConn(conn, websocket.CloseInternalServerErr, "failed to get order")
+				return
+			}
+
+			if !o.CourierTracked() {
+				closeConn(conn, websocket.CloseNormalClosure, fmt.Sprintf("order is %s", o.State()))
+				return
+			}
+		case <-ping.C:
+			if err = conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(locationsWriteWait)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// CheckOrigin returns function which accepts WebSocket handshake of a request coming from one of allowed origins,
+// e.g. "https://example.com". Same-origin request and request without Origin header, which is not made by a browser,
+// are accepted too. Origin "*" allows any origin.
+func CheckOrigin(allowed []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+
+		if u, err := url.Parse(origin); err == nil && strings.EqualFold(u.Host, r.Host) {
+			return true
+		}
+
+		for _, a := range allowed {
+			if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// readControl reads messages of subscriber, so pongs and close are handled, and cancels relaying once it fails.
+// Subscriber is not expected to send data messages, they are discarded.
+func readControl(conn *websocket.Conn, cancel context.CancelFunc) {
+	defer cancel()
+
+	_ = conn.SetReadDeadline(time.Now().Add(locationsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(locationsPongWait))
+	})
+
+	for {
+		if _, _, err := conn.NextReader(); err != nil {
+			return
+		}
+	}
+}
+
+func writeLocation(conn *websocket.Conn, l order.CourierLocation) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(locationsWriteWait)); err != nil {
+		return err
+	}
+
+	return conn.WriteJSON(locationResponse(l))
+}
+
+func closeConn(conn *websocket.Conn, code int, reason string) {
+	message := websocket.FormatCloseMessage(code, reason)
+	_ = conn.WriteControl(websocket.CloseMessage, message, time.Now().Add(locationsWriteWait))
+}
+
+func locationResponse(l order.CourierLocation) CourierLocationResponse {
+	return CourierLocationResponse{
+		OrderID:   l.OrderID,
+		CourierID: l.CourierID,
+		Latitude:  l.Position.Latitude(),
+		Longitude: l.Position.Longitude(),
+		At:        l.At,
+	}
+}
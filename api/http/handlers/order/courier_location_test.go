@@ -0,0 +1,38 @@
+package order_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	handlers "service/api/http/handlers/order"
+	"testing"
+)
+
+func TestCheckOrigin(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name    string
+		allowed []string
+		origin  string
+		ok      bool
+	}{
+		{"no origin", nil, "", true},
+		{"same origin", nil, "https://service.example.com", true},
+		{"cross origin by default", nil, "https://app.example.com", false},
+		{"allowed origin", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"allowed origin with trailing slash", []string{"https://APP.example.com/"}, "https://app.example.com", true},
+		{"not allowed origin", []string{"https://app.example.com"}, "https://evil.example.com", false},
+		{"not allowed scheme", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"any origin", []string{"*"}, "https://evil.example.com", true},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "https://service.example.com/api/v1/order/id/courier/location/ws", nil)
+			if tc.origin != "" {
+				r.Header.Set("Origin", tc.origin)
+			}
+
+			assert.Equal(t, tc.ok, handlers.CheckOrigin(tc.allowed)(r))
+		})
+	}
+}
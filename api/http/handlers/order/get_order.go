@@ -6,16 +6,68 @@ import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"net/http"
+	"service/domain/order"
+	"service/domain/shared/destination"
 	"service/http/httpstatus"
 	"time"
 )
 
 type GetOrderResponse struct { //nolint:govet
-	ID            uuid.UUID `json:"id"`
-	State         string    `json:"state"`
-	TransactionID string    `json:"transaction_id"`
-	CourierID     string    `json:"courier_id"`
-	Timestamp     time.Time `json:"timestamp"`
+	ID            uuid.UUID             `json:"id"`
+	CustomerID    uuid.UUID             `json:"customer_id"`
+	RestaurantID  uuid.UUID             `json:"restaurant_id"`
+	State         string                `json:"state"`
+	TransactionID string                `json:"transaction_id"`
+	CourierID     string                `json:"courier_id"`
+	Assignments   []AssignmentResponse  `json:"courier_assignments"`
+	Fulfillment   string                `json:"fulfillment"`
+	Destination   *DestinationResponse  `json:"destination,omitempty"`
+	Items         []ItemResponse        `json:"items"`
+	Pricing       PricingResponse       `json:"pricing"`
+	PaidAmount    int64                 `json:"paid_amount"`
+	Cancellation  *CancellationResponse `json:"cancellation,omitempty"`
+	DeliverAt     *time.Time            `json:"deliver_at,omitempty"`
+	ETA           *time.Time            `json:"eta,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	Timestamp     time.Time             `json:"timestamp"`
+}
+
+type CancellationResponse struct {
+	Reason     string    `json:"reason"`
+	CanceledBy string    `json:"canceled_by"`
+	CanceledAt time.Time `json:"canceled_at"`
+}
+
+type DestinationResponse struct {
+	Latitude  float64                  `json:"latitude"`
+	Longitude float64                  `json:"longitude"`
+	Address   *destination.JSONAddress `json:"address,omitempty"`
+}
+
+type AssignmentResponse struct { //nolint:govet
+	CourierID  uuid.UUID  `json:"courier_id"`
+	AssignedAt time.Time  `json:"assigned_at"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	Reason     string     `json:"reason,omitempty"`
+}
+
+type PricingResponse struct {
+	Currency    string `json:"currency"`
+	Subtotal    int64  `json:"subtotal"`
+	DeliveryFee int64  `json:"delivery_fee"`
+	ServiceFee  int64  `json:"service_fee"`
+	Tax         int64  `json:"tax"`
+	Tip         int64  `json:"tip"`
+	Discount    int64  `json:"discount"`
+	Total       int64  `json:"total"`
+}
+
+type ItemResponse struct { //nolint:govet
+	MealID    uuid.UUID `json:"meal_id"`
+	Notes     string    `json:"notes,omitempty"`
+	UnitPrice int64     `json:"unit_price"`
+	Quantity  int       `json:"quantity"`
+	Total     int64     `json:"total"`
 }
 
 func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
@@ -28,18 +80,119 @@ func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request) {
 	}
 
 	o, err := h.repository.Get(ctx, id)
-	if err != nil {
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case err != nil:
 		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get order"))
 		return
 	}
 
-	response := GetOrderResponse{
+	render.JSON(w, r, orderResponse(o, time.Now()))
+}
+
+// orderResponse represents order as of now.
+func orderResponse(o *order.Order, now time.Time) GetOrderResponse {
+	return GetOrderResponse{
 		ID:            o.ID(),
+		CustomerID:    o.CustomerID(),
+		RestaurantID:  o.RestaurantID(),
 		State:         o.State().String(),
 		TransactionID: o.TransactionID().String(),
 		CourierID:     o.CourierID().String(),
-		Timestamp:     time.Now(),
+		Assignments:   assignmentsResponse(o.Assignments()),
+		Fulfillment:   o.Fulfillment().String(),
+		Destination:   destinationResponse(o.Destination()),
+		Items:         itemsResponse(o.Items()),
+		Pricing:       pricingResponse(o.Pricing()),
+		PaidAmount:    o.PaidAmount().Amount(),
+		Cancellation:  cancellationResponse(o.Cancellation()),
+		DeliverAt:     timeResponse(o.DeliverAt()),
+		ETA:           timeResponse(o.ETA()),
+		CreatedAt:     o.CreateAt(),
+		Timestamp:     now,
+	}
+}
+
+func itemsResponse(items []order.Item) []ItemResponse {
+	response := make([]ItemResponse, len(items))
+
+	for i, item := range items {
+		response[i] = ItemResponse{
+			MealID:    item.MealID(),
+			Notes:     item.Notes(),
+			UnitPrice: item.UnitPrice(),
+			Quantity:  item.Quantity(),
+			Total:     item.Total(),
+		}
+	}
+
+	return response
+}
+
+func destinationResponse(d destination.Destination) *DestinationResponse {
+	if d.IsZero() {
+		return nil
+	}
+
+	response := &DestinationResponse{
+		Latitude:  d.Latitude(),
+		Longitude: d.Longitude(),
+	}
+
+	if !d.Address().IsZero() {
+		address := d.Address().ToJSON()
+		response.Address = &address
+	}
+
+	return response
+}
+
+func assignmentsResponse(assignments []order.CourierAssignment) []AssignmentResponse {
+	response := make([]AssignmentResponse, len(assignments))
+
+	for i, assignment := range assignments {
+		response[i] = AssignmentResponse{
+			CourierID:  assignment.CourierID(),
+			AssignedAt: assignment.AssignedAt(),
+			ReleasedAt: timeResponse(assignment.ReleasedAt()),
+			Reason:     assignment.Reason(),
+		}
+	}
+
+	return response
+}
+
+func pricingResponse(p order.Pricing) PricingResponse {
+	return PricingResponse{
+		Currency:    p.Currency().String(),
+		Subtotal:    p.Subtotal().Amount(),
+		DeliveryFee: p.DeliveryFee().Amount(),
+		ServiceFee:  p.ServiceFee().Amount(),
+		Tax:         p.Tax().Amount(),
+		Tip:         p.Tip().Amount(),
+		Discount:    p.Discount().Amount(),
+		Total:       p.Total().Amount(),
+	}
+}
+
+func cancellationResponse(c order.Cancellation) *CancellationResponse {
+	if c.IsZero() {
+		return nil
+	}
+
+	return &CancellationResponse{
+		Reason:     c.Reason(),
+		CanceledBy: c.By().String(),
+		CanceledAt: c.At(),
+	}
+}
+
+func timeResponse(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
 
-	render.JSON(w, r, response)
+	return &t
 }
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"service/domain/order"
+	"service/http/httpstatus"
+	"time"
+)
+
+type GetOrderHistoryResponse struct {
+	OrderID uuid.UUID             `json:"order_id"`
+	History []StateChangeResponse `json:"history"`
+}
+
+type StateChangeResponse struct { //nolint:govet
+	ID          uuid.UUID  `json:"id"`
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	Actor       string     `json:"actor"`
+	CausationID string     `json:"causation_id,omitempty"`
+	At          time.Time  `json:"at"`
+	ETA         *time.Time `json:"eta,omitempty"`
+}
+
+func (h *Handler) GetOrderHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	o, err := h.repository.Get(ctx, id)
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get order"))
+		return
+	}
+
+	history := o.History()
+
+	response := GetOrderHistoryResponse{
+		OrderID: o.ID(),
+		History: make([]StateChangeResponse, len(history)),
+	}
+
+	for i, change := range history {
+		response.History[i] = stateChangeResponse(change)
+	}
+
+	render.JSON(w, r, response)
+}
+
+func stateChangeResponse(change order.StateChange) StateChangeResponse {
+	return StateChangeResponse{
+		ID:          change.ID(),
+		From:        change.From().String(),
+		To:          change.To().String(),
+		Actor:       change.Actor().String(),
+		CausationID: change.CausationID(),
+		ETA:         timeResponse(change.ETA()),
+		At:          change.At(),
+	}
+}
@@ -1,30 +1,78 @@
 package order
 
 import (
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"go.opentelemetry.io/otel/trace"
 	"service/domain/order"
+	"service/domain/shared/idempotency"
+	"service/domain/shared/modifier"
 	"service/domain/shared/saver"
+	"service/domain/shared/zone"
 )
 
+// OrderService saves, modifies and cancels orders and tells other services about it.
+type OrderService interface {
+	saver.Saver[*order.Order]
+	modifier.Modifier[uuid.UUID, *order.Order]
+	Canceler
+}
+
 type Handler struct {
 	tracer trace.Tracer
 
-	saverService saver.Saver[*order.Order]
+	// orderService saves, modifies and cancels orders and tells other services about it.
+	orderService OrderService
+
+	// zones provides restaurants delivery zones.
+	zones zone.Provider
+
+	// keys keeps orders taken with idempotency keys.
+	keys idempotency.Store
+
+	// onceSaver saves orders taken with idempotency keys.
+	onceSaver IdempotentSaver
+
+	// changes notifies about orders which have changed their state.
+	changes ChangeSubscriber
+
+	// locations keeps locations of couriers delivering orders.
+	locations order.LocationTracker
+
+	// upgrader upgrades connections of courier locations subscribers.
+	upgrader websocket.Upgrader
 
 	// metrics
 
 	// repositories eg.
 	repository order.Repository
+
+	// queries lists orders.
+	queries order.Querier
 }
 
 func NewHandler(
 	tracer trace.Tracer,
 	repository order.Repository,
-	saverService saver.Saver[*order.Order],
+	queries order.Querier,
+	orderService OrderService,
+	zones zone.Provider,
+	keys idempotency.Store,
+	onceSaver IdempotentSaver,
+	changes ChangeSubscriber,
+	locations order.LocationTracker,
+	allowedOrigins []string,
 ) *Handler {
 	return &Handler{
 		tracer:       tracer,
 		repository:   repository,
-		saverService: saverService,
+		queries:      queries,
+		orderService: orderService,
+		zones:        zones,
+		keys:         keys,
+		onceSaver:    onceSaver,
+		changes:      changes,
+		locations:    locations,
+		upgrader:     websocket.Upgrader{CheckOrigin: CheckOrigin(allowedOrigins)},
 	}
 }
@@ -0,0 +1,136 @@
+package order
+
+import (
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"service/domain/order"
+	"service/http/httpstatus"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ListOrdersResponse struct {
+	Orders []GetOrderResponse `json:"orders"`
+
+	// NextCursor is passed as cursor to get the next page. It is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListOrders lists orders page by page.
+//
+// Query parameters:
+//   - customer_id, restaurant_id, courier_id select orders of the party;
+//   - state selects orders in any of comma separated states, it may be repeated;
+//   - created_from, created_to select orders created in [created_from, created_to), RFC 3339;
+//   - sort is either "-created_at" (default) or "created_at";
+//   - limit is the page size, see [order.DefaultListLimit] and [order.MaxListLimit];
+//   - cursor is next_cursor of the previous page.
+func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	q, err := listQuery(r.URL.Query())
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	}
+
+	page, err := h.queries.List(ctx, q)
+	if err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to list orders"))
+		return
+	}
+
+	now := time.Now()
+
+	response := ListOrdersResponse{
+		Orders: make([]GetOrderResponse, len(page.Orders)),
+	}
+
+	for i, o := range page.Orders {
+		response.Orders[i] = orderResponse(o, now)
+	}
+
+	if page.Next != nil {
+		response.NextCursor = page.Next.String()
+	}
+
+	render.JSON(w, r, response)
+}
+
+// listQuery parses list query from URL query parameters.
+func listQuery(values url.Values) (order.ListQuery, error) {
+	var (
+		q    = order.ListQuery{Limit: order.DefaultListLimit}
+		errs error
+		err  error
+	)
+
+	for param, id := range map[string]*uuid.UUID{
+		"customer_id":   &q.Filter.CustomerID,
+		"restaurant_id": &q.Filter.RestaurantID,
+		"courier_id":    &q.Filter.CourierID,
+	} {
+		if raw := values.Get(param); raw != "" {
+			if *id, err = uuid.Parse(raw); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "invalid %s", param))
+			}
+		}
+	}
+
+	for _, raw := range values["state"] {
+		for _, name := range strings.Split(raw, ",") {
+			state, stateErr := order.ParseState(strings.TrimSpace(name))
+			if stateErr != nil {
+				errs = multierror.Append(errs, stateErr)
+				continue
+			}
+
+			q.Filter.States = append(q.Filter.States, state)
+		}
+	}
+
+	for param, at := range map[string]*time.Time{
+		"created_from": &q.Filter.CreatedFrom,
+		"created_to":   &q.Filter.CreatedTo,
+	} {
+		if raw := values.Get(param); raw != "" {
+			if *at, err = time.Parse(time.RFC3339, raw); err != nil {
+				errs = multierror.Append(errs, errors.Wrapf(err, "invalid %s", param))
+			}
+		}
+	}
+
+	if q.Sort, err = order.ParseSort(values.Get("sort")); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	if raw := values.Get("limit"); raw != "" {
+		if q.Limit, err = strconv.Atoi(raw); err != nil {
+			errs = multierror.Append(errs, errors.Wrap(err, "invalid limit"))
+		}
+	}
+
+	if raw := values.Get("cursor"); raw != "" {
+		cursor, cursorErr := order.ParseCursor(raw)
+		if cursorErr != nil {
+			errs = multierror.Append(errs, cursorErr)
+		}
+
+		q.After = &cursor
+	}
+
+	if errs != nil {
+		return order.ListQuery{}, errs
+	}
+
+	if err = q.Validate(); err != nil {
+		return order.ListQuery{}, err
+	}
+
+	return q, nil
+}
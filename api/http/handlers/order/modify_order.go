@@ -0,0 +1,96 @@
+package order
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"net/http"
+	"service/domain/order"
+	"service/http/httpstatus"
+	"time"
+)
+
+type ModifyOrderRequest struct {
+	Items []ItemRequest `json:"items"`
+}
+
+type ModifyOrderResponse struct { //nolint:govet
+	OrderID uuid.UUID       `json:"order_id"`
+	Items   []ItemResponse  `json:"items"`
+	Pricing PricingResponse `json:"pricing"`
+
+	// Balance is the amount customer owes in addition to the paid one, it is negative if customer is owed a refund.
+	Balance   int64     `json:"balance"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// invalidItemsError is returned by order modification if requested items cannot replace order`s ones.
+type invalidItemsError struct {
+	err error
+}
+
+func (e *invalidItemsError) Error() string { return e.err.Error() }
+func (e *invalidItemsError) Unwrap() error { return e.err }
+
+func (h *Handler) ModifyOrder(w http.ResponseWriter, r *http.Request) {
+	var (
+		ctx, span = h.tracer.Start(r.Context(), "modify order")
+	)
+
+	defer span.End()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	modifyOrder := &ModifyOrderRequest{}
+
+	err = render.DecodeJSON(r.Body, modifyOrder)
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, err)
+		return
+	}
+
+	o, err := h.orderService.Modify(ctx, id, func(o *order.Order) error {
+		_, modifyErr := order.NewStateOperator(o,
+			order.WithActor(order.ActorCustomer),
+		).ModifyItems(itemsParams(modifyOrder.Items))
+		if modifyErr != nil && !errors.Is(modifyErr, order.ErrOrderNotModifiable) {
+			return &invalidItemsError{err: modifyErr}
+		}
+
+		return modifyErr
+	})
+
+	var invalidItems *invalidItemsError
+
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case errors.Is(err, order.ErrOrderNotModifiable), errors.Is(err, order.ErrConcurrentModification):
+		httpstatus.Conflict(ctx, w, err)
+		return
+	case errors.As(err, &invalidItems):
+		httpstatus.BadRequest(ctx, w, invalidItems.err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to modify order"))
+		return
+	}
+
+	span.AddEvent("modified order")
+
+	response := ModifyOrderResponse{
+		OrderID:   o.ID(),
+		Items:     itemsResponse(o.Items()),
+		Pricing:   pricingResponse(o.Pricing()),
+		Balance:   o.Balance().Amount(),
+		Timestamp: time.Now(),
+	}
+
+	httpstatus.Ok(w, response)
+}
@@ -0,0 +1,145 @@
+package order
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"net/http"
+	"service/domain/order"
+	"service/http/httpstatus"
+	"time"
+)
+
+const (
+	// LastEventIDHeader is sent by reconnecting client with id of the last received event.
+	LastEventIDHeader = "Last-Event-ID"
+
+	// stateChangedEvent is the type of events streamed by OrderEvents.
+	stateChangedEvent = "state_changed"
+
+	// eventsHeartbeatInterval is how often a comment is streamed to keep idle connection open.
+	eventsHeartbeatInterval = 15 * time.Second
+)
+
+// ChangeSubscriber notifies about orders which have changed their state.
+type ChangeSubscriber interface {
+	// Subscribe returns channel which receives a value when order with id has changed.
+	// Channel is closed when ctx is done or subscriber is shut down.
+	Subscribe(ctx context.Context, id uuid.UUID) (<-chan struct{}, error)
+}
+
+// OrderEvents streams every state change of the order as Server-Sent Events. Event id is the state change id.
+// History is streamed first, reconnecting client gets only changes after [LastEventIDHeader].
+// Stream ends when order is closed or server shuts down.
+func (h *Handler) OrderEvents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := uuid.Parse(chi.URLParam(r, "uuid"))
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid order id"))
+		return
+	}
+
+	var last uuid.UUID
+
+	if raw := r.Header.Get(LastEventIDHeader); raw != "" {
+		if last, err = uuid.Parse(raw); err != nil {
+			httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid last event id"))
+			return
+		}
+	}
+
+	// subscribed before order is read, so a change made in between is not missed
+	changes, err := h.changes.Subscribe(ctx, id)
+	if err != nil {
+		httpstatus.ServiceUnavailable(ctx, w, errors.Wrap(err, "failed to subscribe to order changes"))
+		return
+	}
+
+	o, err := h.repository.Get(ctx, id)
+	switch {
+	case errors.Is(err, order.ErrOrderNotFound):
+		httpstatus.NotFound(ctx, w, err)
+		return
+	case err != nil:
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to get order"))
+		return
+	}
+
+	rc := http.NewResponseController(w)
+
+	// stream outlives server write timeout
+	if err = rc.SetWriteDeadline(time.Time{}); err != nil && !errors.Is(err, http.ErrNotSupported) {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to reset write deadline"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	heartbeat := time.NewTicker(eventsHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		if last, err = writeStateChanges(w, o.History(), last); err != nil {
+			return
+		}
+
+		if err = rc.Flush(); err != nil {
+			return
+		}
+
+		if o.Is(order.Closed) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-changes:
+			if !ok {
+				return
+			}
+
+			if o, err = h.repository.Get(ctx, id); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err = io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// writeStateChanges writes events of changes made after last one and returns id of the last written change.
+// Every change is written if last is not found in history.
+func writeStateChanges(w io.Writer, history []order.StateChange, last uuid.UUID) (uuid.UUID, error) {
+	for i, change := range history {
+		if change.ID() == last {
+			history = history[i+1:]
+			break
+		}
+	}
+
+	for _, change := range history {
+		data, err := json.Marshal(stateChangeResponse(change))
+		if err != nil {
+			return last, errors.Wrap(err, "failed to encode state change")
+		}
+
+		if _, err = fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", change.ID(), stateChangedEvent, data); err != nil {
+			return last, errors.Wrap(err, "failed to write state change")
+		}
+
+		last = change.ID()
+	}
+
+	return last, nil
+}
@@ -1,21 +1,75 @@
 package order
 
 import (
+	"context"
+	"encoding/json"
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"net/http"
 	"service/domain/order"
+	"service/domain/shared/destination"
+	"service/domain/shared/idempotency"
+	"service/domain/shared/zone"
 	"service/http/httpstatus"
 	"time"
 )
 
+const (
+	// IdempotencyKeyHeader is an optional key of the request which makes its retries take order once.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayedHeader is set on the response replayed for a retried request.
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// IdempotentSaver saves order taken with idempotency key and stores response of the request in one transaction,
+// so key is completed if and only if order is saved.
+type IdempotentSaver interface {
+	SaveOnce(ctx context.Context, o *order.Order, reserved idempotency.Record, response []byte) error
+}
+
 type TakeOrderRequest struct {
-	CustomerID   string   `json:"customer_id"`
-	RestaurantID string   `json:"restaurant_id"`
-	Meals        []string `json:"meals"`
-	Destination  struct {
-		Latitude  float64 `json:"latitude"`
-		Longitude float64 `json:"longitude"`
+	CustomerID   string        `json:"customer_id"`
+	RestaurantID string        `json:"restaurant_id"`
+	Items        []ItemRequest `json:"items"`
+	Currency     string        `json:"currency"`
+	Charges      struct {
+		DeliveryFee int64 `json:"delivery_fee"`
+		ServiceFee  int64 `json:"service_fee"`
+		Tax         int64 `json:"tax"`
+		Tip         int64 `json:"tip"`
+		Discount    int64 `json:"discount"`
+	} `json:"charges"`
+	// Fulfillment is either delivery or pickup. Order is delivered by default.
+	Fulfillment string `json:"fulfillment,omitempty"`
+	// Destination is optional for pickup order.
+	Destination struct {
+		Latitude  float64         `json:"latitude"`
+		Longitude float64         `json:"longitude"`
+		Address   *AddressRequest `json:"address,omitempty"`
 	} `json:"destination"`
+	// DeliverAt is an optional time customer wants order to be delivered at.
+	DeliverAt *time.Time `json:"deliver_at,omitempty"`
+}
+
+type AddressRequest struct { //nolint:govet
+	Line1        string `json:"line1"`
+	Line2        string `json:"line2"`
+	City         string `json:"city"`
+	PostalCode   string `json:"postal_code"`
+	Entrance     string `json:"entrance"`
+	Floor        string `json:"floor"`
+	Apartment    string `json:"apartment"`
+	Instructions string `json:"instructions"`
+	Contactless  bool   `json:"contactless"`
+}
+
+type ItemRequest struct {
+	MealID    string `json:"meal_id"`
+	Notes     string `json:"notes"`
+	UnitPrice int64  `json:"unit_price"`
+	Quantity  int    `json:"quantity"`
 }
 
 type TakeOrderResponse struct { //nolint:govet
@@ -23,6 +77,11 @@ type TakeOrderResponse struct { //nolint:govet
 	Timestamp time.Time `json:"timestamp"`
 }
 
+// TakeOrder takes a new order.
+//
+// Request retried with the same [IdempotencyKeyHeader] gets the response of the original request
+// and does not take another order. Key reused by the same customer with another request is rejected with 422.
+// Keys of different customers never collide.
 func (h *Handler) TakeOrder(w http.ResponseWriter, r *http.Request) {
 	var (
 		ctx, span = h.tracer.Start(r.Context(), "take order")
@@ -38,31 +97,215 @@ func (h *Handler) TakeOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// stored tells if order taken with idempotency key has been saved. Otherwise key is released on return,
+	// so request may be retried. Key of the stored order is never released, so a retry does not take another order.
+	key, stored := r.Header.Get(IdempotencyKeyHeader), false
+
+	var reserved *idempotency.Record
+
+	if key != "" {
+		record, ok := h.reserveKey(ctx, w, key, takeOrder)
+		if !ok {
+			return
+		}
+
+		reserved = &record
+
+		defer func() {
+			if !stored {
+				if releaseErr := h.keys.Release(context.WithoutCancel(ctx), *reserved); releaseErr != nil {
+					span.RecordError(errors.Wrap(releaseErr, "failed to release idempotency key"))
+				}
+			}
+		}()
+	}
+
+	z, zoned, err := h.restaurantZone(ctx, takeOrder.RestaurantID)
+	if err != nil {
+		httpstatus.InternalServerError(ctx, w, err)
+		return
+	}
+
+	opts := takeOrderOptions(takeOrder)
+
+	if zoned && !z.Center().IsZero() {
+		// restaurant location is used to estimate delivery time
+		opts = append(opts, order.WithRestaurantLocation(z.Center().Latitude(), z.Center().Longitude()))
+	}
+
 	o, err := order.NewOrder(
 		takeOrder.RestaurantID,
 		takeOrder.CustomerID,
-		takeOrder.Meals,
+		itemsParams(takeOrder.Items),
+		order.Charges{
+			Currency:    takeOrder.Currency,
+			DeliveryFee: takeOrder.Charges.DeliveryFee,
+			ServiceFee:  takeOrder.Charges.ServiceFee,
+			Tax:         takeOrder.Charges.Tax,
+			Tip:         takeOrder.Charges.Tip,
+			Discount:    takeOrder.Charges.Discount,
+		},
 		takeOrder.Destination.Latitude,
 		takeOrder.Destination.Longitude,
+		opts...,
 	)
 	if err != nil {
 		httpstatus.BadRequest(ctx, w, err)
 		return
 	}
 
-	err = h.saverService.Save(ctx, o)
+	if zoned {
+		if err = o.CheckDeliveryZone(z); err != nil {
+			httpstatus.UnprocessableEntity(ctx, w, err)
+			return
+		}
+	}
+
+	response := TakeOrderResponse{
+		OrderID:   o.ID().String(),
+		Timestamp: o.CreateAt(),
+	}
+
+	err = h.save(ctx, o, reserved, response)
 	if err != nil {
 		// should be bad request or internal server error
 		httpstatus.InternalServerError(ctx, w, err)
 		return
 	}
 
+	stored = true
+
 	span.AddEvent("created order")
 
-	response := TakeOrderResponse{
-		OrderID:   o.ID().String(),
-		Timestamp: o.CreateAt(),
+	httpstatus.Created(w, response)
+}
+
+// reserveKey reserves idempotency key of the customer for the request and returns the reservation.
+// It returns false if response has been already written:
+// either request is a retry and the original response is replayed, or key can't be used for it.
+func (h *Handler) reserveKey(ctx context.Context, w http.ResponseWriter, key string, takeOrder *TakeOrderRequest) (idempotency.Record, bool) {
+	if err := idempotency.ValidateKey(key); err != nil {
+		httpstatus.BadRequest(ctx, w, err)
+		return idempotency.Record{}, false
+	}
+
+	customerID, err := uuid.Parse(takeOrder.CustomerID)
+	if err != nil {
+		httpstatus.BadRequest(ctx, w, errors.Wrap(err, "invalid customer id"))
+		return idempotency.Record{}, false
 	}
 
+	fingerprint, err := idempotency.Fingerprint(takeOrder)
+	if err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to fingerprint request"))
+		return idempotency.Record{}, false
+	}
+
+	record, reserved, err := h.keys.Reserve(ctx, idempotency.Key{CustomerID: customerID, Value: key}, fingerprint)
+	if err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to reserve idempotency key"))
+		return idempotency.Record{}, false
+	}
+
+	if reserved {
+		return record, true
+	}
+
+	err = record.Check(fingerprint)
+	switch {
+	case errors.Is(err, idempotency.ErrFingerprintMismatch):
+		httpstatus.UnprocessableEntity(ctx, w, err)
+		return idempotency.Record{}, false
+	case errors.Is(err, idempotency.ErrRequestInProgress):
+		httpstatus.Conflict(ctx, w, err)
+		return idempotency.Record{}, false
+	}
+
+	response := TakeOrderResponse{}
+	if err = json.Unmarshal(record.Response, &response); err != nil {
+		httpstatus.InternalServerError(ctx, w, errors.Wrap(err, "failed to decode replayed response"))
+		return idempotency.Record{}, false
+	}
+
+	w.Header().Set(IdempotentReplayedHeader, "true")
 	httpstatus.Created(w, response)
+
+	return idempotency.Record{}, false
+}
+
+// save saves order. Order taken with reserved idempotency key is saved along with response of the request,
+// see [IdempotentSaver].
+func (h *Handler) save(ctx context.Context, o *order.Order, reserved *idempotency.Record, response TakeOrderResponse) error {
+	if reserved == nil {
+		return h.orderService.Save(ctx, o)
+	}
+
+	bytes, err := json.Marshal(response)
+	if err != nil {
+		return errors.Wrap(err, "failed to encode response")
+	}
+
+	return h.onceSaver.SaveOnce(ctx, o, *reserved, bytes)
+}
+
+// restaurantZone returns delivery zone of the restaurant and true if restaurant has it.
+// Restaurant without a zone delivers anywhere. Invalid restaurant id has no zone: it is rejected along with the order.
+func (h *Handler) restaurantZone(ctx context.Context, restaurantID string) (zone.Zone, bool, error) {
+	id, err := uuid.Parse(restaurantID)
+	if err != nil {
+		return zone.Zone{}, false, nil
+	}
+
+	z, err := h.zones.Zone(ctx, id)
+	switch {
+	case errors.Is(err, zone.ErrZoneNotFound):
+		return zone.Zone{}, false, nil
+	case err != nil:
+		return zone.Zone{}, false, errors.Wrap(err, "failed to get delivery zone")
+	}
+
+	return z, true, nil
+}
+
+func itemsParams(items []ItemRequest) []order.ItemParams {
+	params := make([]order.ItemParams, len(items))
+
+	for i, item := range items {
+		params[i] = order.ItemParams{
+			MealID:    item.MealID,
+			Notes:     item.Notes,
+			UnitPrice: item.UnitPrice,
+			Quantity:  item.Quantity,
+		}
+	}
+
+	return params
+}
+
+func takeOrderOptions(takeOrder *TakeOrderRequest) []order.Option {
+	var opts []order.Option
+
+	if takeOrder.Fulfillment != "" {
+		opts = append(opts, order.WithFulfillment(order.Fulfillment(takeOrder.Fulfillment)))
+	}
+
+	if address := takeOrder.Destination.Address; address != nil {
+		opts = append(opts, order.WithAddress(destination.AddressParams{
+			Line1:        address.Line1,
+			Line2:        address.Line2,
+			City:         address.City,
+			PostalCode:   address.PostalCode,
+			Entrance:     address.Entrance,
+			Floor:        address.Floor,
+			Apartment:    address.Apartment,
+			Instructions: address.Instructions,
+			Contactless:  address.Contactless,
+		}))
+	}
+
+	if takeOrder.DeliverAt != nil {
+		opts = append(opts, order.WithDeliveryTime(*takeOrder.DeliverAt))
+	}
+
+	return opts
 }
@@ -0,0 +1,71 @@
+package order
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"service/domain/order"
+	"time"
+)
+
+// UnpaidCanceler cancels order which has been waiting for payment since before awaitingBefore.
+// It must leave order untouched if order has been paid meanwhile.
+type UnpaidCanceler interface {
+	CancelUnpaid(ctx context.Context, id uuid.UUID, awaitingBefore time.Time) error
+}
+
+// PaymentTimeoutHandler cancels orders which have not been paid in time.
+type PaymentTimeoutHandler struct {
+	logger   *zerolog.Logger
+	finder   order.UnpaidFinder
+	canceler UnpaidCanceler
+
+	// ttl is how long order waits for payment.
+	ttl time.Duration
+
+	// batch is the maximum number of orders processed by one job run.
+	batch int
+}
+
+func NewPaymentTimeoutHandler(
+	logger *zerolog.Logger,
+	finder order.UnpaidFinder,
+	canceler UnpaidCanceler,
+	ttl time.Duration,
+	batch int,
+) *PaymentTimeoutHandler {
+	return &PaymentTimeoutHandler{
+		logger:   logger,
+		finder:   finder,
+		canceler: canceler,
+		ttl:      ttl,
+		batch:    batch,
+	}
+}
+
+// CancelUnpaid cancels orders which have been waiting for payment longer than ttl.
+// Several consumers may run the job at once: order is canceled by the first of them,
+// the others find it already canceled and leave it untouched.
+func (h *PaymentTimeoutHandler) CancelUnpaid(ctx context.Context) error {
+	awaitingBefore := time.Now().Add(-h.ttl)
+
+	ids, err := h.finder.FindUnpaid(ctx, awaitingBefore, h.batch)
+	if err != nil {
+		return errors.Wrap(err, "failed to find unpaid orders")
+	}
+
+	var errs error
+
+	for _, id := range ids {
+		if err = h.canceler.CancelUnpaid(ctx, id, awaitingBefore); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to cancel unpaid order: %s", id))
+			continue
+		}
+
+		h.logger.Info().Str("order-id", id.String()).Msg("canceled unpaid order")
+	}
+
+	return errs
+}
@@ -0,0 +1,49 @@
+package order
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// IdempotencyKeysCleaner removes idempotency keys orders have been taken with.
+type IdempotencyKeysCleaner interface {
+	DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// IdempotencyHandler keeps idempotency keys from growing.
+type IdempotencyHandler struct {
+	logger  *zerolog.Logger
+	cleaner IdempotencyKeysCleaner
+
+	// retention is how long idempotency key is kept.
+	// Request retried after retention takes another order.
+	retention time.Duration
+}
+
+func NewIdempotencyHandler(
+	logger *zerolog.Logger,
+	cleaner IdempotencyKeysCleaner,
+	retention time.Duration,
+) *IdempotencyHandler {
+	return &IdempotencyHandler{
+		logger:    logger,
+		cleaner:   cleaner,
+		retention: retention,
+	}
+}
+
+// CleanupIdempotencyKeys removes keys created earlier than retention ago.
+func (h *IdempotencyHandler) CleanupIdempotencyKeys(ctx context.Context) error {
+	deleted, err := h.cleaner.DeleteCreatedBefore(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		return errors.Wrap(err, "failed to clean up idempotency keys")
+	}
+
+	if deleted > 0 {
+		h.logger.Info().Int64("deleted", deleted).Msg("cleaned up idempotency keys")
+	}
+
+	return nil
+}
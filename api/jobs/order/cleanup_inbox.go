@@ -0,0 +1,49 @@
+package order
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// InboxCleaner removes records of processed messages.
+type InboxCleaner interface {
+	DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// InboxHandler keeps inbox of processed messages from growing.
+type InboxHandler struct {
+	logger  *zerolog.Logger
+	cleaner InboxCleaner
+
+	// retention is how long processed message is kept.
+	// Message redelivered after retention is processed again.
+	retention time.Duration
+}
+
+func NewInboxHandler(
+	logger *zerolog.Logger,
+	cleaner InboxCleaner,
+	retention time.Duration,
+) *InboxHandler {
+	return &InboxHandler{
+		logger:    logger,
+		cleaner:   cleaner,
+		retention: retention,
+	}
+}
+
+// CleanupInbox removes messages processed earlier than retention ago.
+func (h *InboxHandler) CleanupInbox(ctx context.Context) error {
+	deleted, err := h.cleaner.DeleteProcessedBefore(ctx, time.Now().Add(-h.retention))
+	if err != nil {
+		return errors.Wrap(err, "failed to clean up inbox")
+	}
+
+	if deleted > 0 {
+		h.logger.Info().Int64("deleted", deleted).Msg("cleaned up inbox")
+	}
+
+	return nil
+}
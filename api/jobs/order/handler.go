@@ -0,0 +1,36 @@
+package order
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"service/domain/order"
+)
+
+// Releaser moves scheduled order into the normal flow.
+type Releaser interface {
+	Release(ctx context.Context, id uuid.UUID) error
+}
+
+type Handler struct {
+	logger   *zerolog.Logger
+	finder   order.ScheduledFinder
+	releaser Releaser
+
+	// batch is the maximum number of orders processed by one job run.
+	batch int
+}
+
+func NewHandler(
+	logger *zerolog.Logger,
+	finder order.ScheduledFinder,
+	releaser Releaser,
+	batch int,
+) *Handler {
+	return &Handler{
+		logger:   logger,
+		finder:   finder,
+		releaser: releaser,
+		batch:    batch,
+	}
+}
@@ -0,0 +1,31 @@
+package order
+
+import (
+	"context"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"time"
+)
+
+// ReleaseScheduled releases scheduled orders which release time has come.
+// Orders are stored in [order.Scheduled] state with their release time,
+// so orders that were due while service was down are released on the next run.
+func (h *Handler) ReleaseScheduled(ctx context.Context) error {
+	ids, err := h.finder.FindDueScheduled(ctx, time.Now(), h.batch)
+	if err != nil {
+		return errors.Wrap(err, "failed to find scheduled orders")
+	}
+
+	var errs error
+
+	for _, id := range ids {
+		if err = h.releaser.Release(ctx, id); err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to release order: %s", id))
+			continue
+		}
+
+		h.logger.Info().Str("order-id", id.String()).Msg("released scheduled order")
+	}
+
+	return errs
+}
@@ -0,0 +1,66 @@
+package order
+
+import (
+	"context"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/rs/zerolog"
+	"service/domain/order"
+	"service/metrics"
+)
+
+// SLAHandler detects orders which have stayed in their state longer than SLA allows.
+type SLAHandler struct {
+	logger   *zerolog.Logger
+	recorder order.BreachRecorder
+	sla      order.SLA
+
+	// batch is the maximum number of breaches of each state recorded by one job run.
+	batch int
+
+	// breaches counts recorded breaches by state.
+	breaches *prometheus.CounterVec
+}
+
+func NewSLAHandler(
+	logger *zerolog.Logger,
+	recorder order.BreachRecorder,
+	sla order.SLA,
+	batch int,
+) *SLAHandler {
+	return &SLAHandler{
+		logger:   logger,
+		recorder: recorder,
+		sla:      sla,
+		batch:    batch,
+		breaches: metrics.NewCounterVec("jobs", "order_sla_breached_total", "state"),
+	}
+}
+
+// MonitorSLA records orders breaching SLA of every limited state.
+// Breach is recorded and counted once even if several consumers run the job at once.
+func (h *SLAHandler) MonitorSLA(ctx context.Context) error {
+	var errs error
+
+	for state, maxDwell := range h.sla {
+		breaches, err := h.recorder.RecordBreaches(ctx, state, maxDwell, h.batch)
+		if err != nil {
+			errs = multierror.Append(errs, errors.Wrapf(err, "failed to record sla breaches: state %s", state))
+			continue
+		}
+
+		for _, breach := range breaches {
+			h.breaches.WithLabelValues(state.String()).Inc()
+
+			h.logger.Warn().
+				Str("order-id", breach.OrderID.String()).
+				Str("state", state.String()).
+				Dur("dwell", breach.Dwell()).
+				Dur("max-dwell", maxDwell).
+				Msg("order breached sla")
+		}
+	}
+
+	return errs
+}
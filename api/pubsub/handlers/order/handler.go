@@ -1,29 +1,85 @@
 package order
 
 import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel/trace"
 	"service/domain/order"
 	"service/event"
+	"service/metrics"
+	"sync"
 )
 
+var (
+	// duplicates counts redelivered messages that were skipped, by handler name.
+	// It is created once, as every Handler reports to the same global registry.
+	duplicates     *prometheus.CounterVec
+	duplicatesOnce sync.Once
+)
+
+func duplicatesCounter() *prometheus.CounterVec {
+	duplicatesOnce.Do(func() {
+		duplicates = metrics.NewCounterVec("consumer", "duplicate_messages_skipped_total", "handler")
+	})
+
+	return duplicates
+}
+
+// Repository operates orders at most once per consumed message.
+type Repository interface {
+	order.Repository
+	order.OnceOperator
+}
+
 type Handler struct {
 	logger      *zerolog.Logger
 	unmarshaler event.Unmarshaler
 	tracer      trace.Tracer
-	repository  order.Repository
+	repository  Repository
+
+	// duplicates counts redelivered messages that were skipped, by handler name.
+	duplicates *prometheus.CounterVec
 }
 
 func NewHandler(
 	logger *zerolog.Logger,
 	unmarshaler event.Unmarshaler,
 	tracer trace.Tracer,
-	repository order.Repository,
+	repository Repository,
 ) *Handler {
 	return &Handler{
 		logger:      logger,
 		unmarshaler: unmarshaler,
 		tracer:      tracer,
 		repository:  repository,
+		duplicates:  duplicatesCounter(),
 	}
 }
+
+// operate applies op to order at most once per msg.
+// Redelivered message is acked without applying op again.
+func (h *Handler) operate(ctx context.Context, msg *message.Message, id uuid.UUID, op order.Operation) error {
+	key := order.MessageKey{
+		MessageID: msg.UUID,
+		Handler:   message.HandlerNameFromCtx(ctx),
+	}
+
+	processed, err := h.repository.OperateOnce(ctx, key, id, op)
+	if err != nil {
+		return err
+	}
+
+	if !processed {
+		h.duplicates.WithLabelValues(key.Handler).Inc()
+		h.logger.Info().
+			Str("message-id", key.MessageID).
+			Str("handler", key.Handler).
+			Str("order-id", id.String()).
+			Msg("skipped duplicate message")
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+package order_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace/noop"
+	handlers "service/api/pubsub/handlers/order"
+	"service/domain/order"
+	"service/domain/order/event"
+	"service/domain/order/ordertest"
+	"service/domain/shared/money"
+	serviceevent "service/event"
+	"testing"
+)
+
+// inMemoryRepository keeps orders and processed messages in memory.
+type inMemoryRepository struct {
+	order.Repository
+	orders    map[uuid.UUID]*order.Order
+	processed map[order.MessageKey]struct{}
+}
+
+func (r *inMemoryRepository) OperateOnce(_ context.Context, key order.MessageKey, id uuid.UUID, op order.Operation) (bool, error) {
+	if _, ok := r.processed[key]; ok {
+		return false, nil
+	}
+
+	if err := op(r.orders[id]); err != nil {
+		return false, err
+	}
+
+	r.processed[key] = struct{}{}
+
+	return true, nil
+}
+
+func waitingForCourierOrder(t *testing.T) *order.Order {
+	t.Helper()
+
+	o := ordertest.NewOrder(t)
+	operator := order.NewStateOperator(o)
+
+	_, err := operator.PayOrder(uuid.New(), o.Total())
+	require.NoError(t, err)
+	_, err = operator.CookOrder()
+	require.NoError(t, err)
+	_, err = operator.OrderFinished()
+	require.NoError(t, err)
+	_, err = operator.WaitForCourier()
+	require.NoError(t, err)
+
+	return o
+}
+
+func TestHandler_SkipsDuplicateMessages(t *testing.T) {
+	o := waitingForCourierOrder(t)
+	repository := &inMemoryRepository{
+		orders:    map[uuid.UUID]*order.Order{o.ID(): o},
+		processed: map[order.MessageKey]struct{}{},
+	}
+
+	logger := zerolog.Nop()
+	handler := handlers.NewHandler(&logger, serviceevent.JSONMarshaler{}, noop.NewTracerProvider().Tracer(""), repository)
+
+	payload, err := json.Marshal(event.JSONCourierTook{OrderID: o.ID(), CourierID: uuid.New()})
+	require.NoError(t, err)
+
+	msg := message.NewMessage(uuid.NewString(), payload)
+
+	require.NoError(t, handler.CookingTaken(msg))
+	require.NoError(t, handler.CookingTaken(msg), "redelivered message must be acked")
+	assert.Len(t, o.Assignments(), 1)
+	assert.Len(t, repository.processed, 1)
+}
+
+func TestHandler_OrderPaid(t *testing.T) {
+	canceledOrder := func(t *testing.T, actor order.Actor, reason string) *order.Order {
+		o := ordertest.NewOrder(t)
+
+		_, err := order.NewStateOperator(o, order.WithActor(actor)).CancelOrder(reason)
+		require.NoError(t, err)
+
+		return o
+	}
+
+	paidMessage := func(t *testing.T, o *order.Order, transactionID uuid.UUID) *message.Message {
+		amount := o.Total().ToJSON()
+		payload, err := json.Marshal(event.JSONEventOrderPaid{
+			OrderID:       o.ID(),
+			TransactionID: transactionID,
+			Amount:        &amount,
+		})
+		require.NoError(t, err)
+
+		return message.NewMessage(uuid.NewString(), payload)
+	}
+
+	withoutAmount, mismatched := ordertest.NewOrder(t), ordertest.NewOrder(t)
+	timedOut := canceledOrder(t, order.ActorSystem, order.ReasonPaymentTimeout)
+	canceled := canceledOrder(t, order.ActorCustomer, "changed mind")
+	closed := canceledOrder(t, order.ActorCustomer, "changed mind")
+
+	_, err := order.NewStateOperator(closed).CloseOrder()
+	require.NoError(t, err)
+
+	repository := &inMemoryRepository{
+		orders: map[uuid.UUID]*order.Order{
+			withoutAmount.ID(): withoutAmount,
+			mismatched.ID():    mismatched,
+			timedOut.ID():      timedOut,
+			canceled.ID():      canceled,
+			closed.ID():        closed,
+		},
+		processed: map[order.MessageKey]struct{}{},
+	}
+
+	logger := zerolog.Nop()
+	handler := handlers.NewHandler(&logger, serviceevent.JSONMarshaler{}, noop.NewTracerProvider().Tracer(""), repository)
+
+	t.Run("assert order is charged its total if amount is absent", func(t *testing.T) {
+		payload, err := json.Marshal(event.JSONEventOrderPaid{OrderID: withoutAmount.ID(), TransactionID: uuid.New()})
+		require.NoError(t, err)
+
+		require.NoError(t, handler.OrderPaid(message.NewMessage(uuid.NewString(), payload)))
+
+		assert.Equal(t, order.Paid, withoutAmount.State())
+		assert.Equal(t, withoutAmount.Total(), withoutAmount.PaidAmount())
+	})
+
+	t.Run("assert payment in another currency is acked", func(t *testing.T) {
+		amount := money.New(1000, "EUR").ToJSON()
+		payload, err := json.Marshal(event.JSONEventOrderPaid{OrderID: mismatched.ID(), TransactionID: uuid.New(), Amount: &amount})
+		require.NoError(t, err)
+
+		require.NoError(t, handler.OrderPaid(message.NewMessage(uuid.NewString(), payload)))
+
+		assert.Equal(t, order.Created, mismatched.State())
+		assert.Equal(t, uuid.Nil, mismatched.TransactionID())
+	})
+
+	t.Run("assert late payment of order canceled on payment timeout is refunded", func(t *testing.T) {
+		transactionID := uuid.New()
+
+		require.NoError(t, handler.OrderPaid(paidMessage(t, timedOut, transactionID)))
+
+		assert.Equal(t, order.RefundRequested, timedOut.State())
+		assert.Equal(t, transactionID, timedOut.TransactionID())
+	})
+
+	t.Run("assert late payment of order canceled by customer is refunded", func(t *testing.T) {
+		transactionID := uuid.New()
+
+		require.NoError(t, handler.OrderPaid(paidMessage(t, canceled, transactionID)))
+
+		assert.Equal(t, order.RefundRequested, canceled.State())
+		assert.Equal(t, transactionID, canceled.TransactionID())
+	})
+
+	t.Run("assert payment of closed order is acked", func(t *testing.T) {
+		require.NoError(t, handler.OrderPaid(paidMessage(t, closed, uuid.New())))
+
+		assert.Equal(t, order.Closed, closed.State())
+		assert.Equal(t, uuid.Nil, closed.TransactionID())
+	})
+}
+
+func TestHandler_OrderCanceled(t *testing.T) {
+	delivering := waitingForCourierOrder(t)
+	operator := order.NewStateOperator(delivering)
+
+	_, err := operator.CourierTookOrder(uuid.New())
+	require.NoError(t, err)
+	_, err = operator.DeliveringOrder()
+	require.NoError(t, err)
+
+	repository := &inMemoryRepository{
+		orders:    map[uuid.UUID]*order.Order{delivering.ID(): delivering},
+		processed: map[order.MessageKey]struct{}{},
+	}
+
+	logger := zerolog.Nop()
+	handler := handlers.NewHandler(&logger, serviceevent.JSONMarshaler{}, noop.NewTracerProvider().Tracer(""), repository)
+
+	canceledMessage := func(t *testing.T, canceledBy string) *message.Message {
+		payload, err := json.Marshal(event.JSONCanceled{
+			OrderID:    delivering.ID(),
+			Reason:     "changed mind",
+			CanceledBy: canceledBy,
+		})
+		require.NoError(t, err)
+
+		return message.NewMessage(uuid.NewString(), payload)
+	}
+
+	t.Run("assert forbidden cancellation is acked", func(t *testing.T) {
+		require.NoError(t, handler.OrderCanceled(canceledMessage(t, order.ActorCustomer.String())))
+
+		assert.Equal(t, order.Delivering, delivering.State())
+		assert.True(t, delivering.Cancellation().IsZero())
+		assert.Len(t, repository.processed, 1)
+	})
+
+	t.Run("assert cancellation by unknown party is acked", func(t *testing.T) {
+		require.NoError(t, handler.OrderCanceled(canceledMessage(t, "stranger")))
+
+		assert.Equal(t, order.Delivering, delivering.State())
+		assert.True(t, delivering.Cancellation().IsZero())
+	})
+}
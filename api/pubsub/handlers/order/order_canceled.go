@@ -5,6 +5,7 @@ import (
 	"github.com/pkg/errors"
 	"service/domain/order"
 	"service/domain/order/event"
+	"service/pubsub"
 )
 
 //TODO: possible to create an interface with operation method, which would be passed to a handler
@@ -13,6 +14,11 @@ import (
 // }
 // To perform an operation, behavior depends on order state(cancel, finished cooking etc.)
 
+var _ message.NoPublishHandlerFunc = ((*Handler)(nil)).OrderCanceled
+
+// OrderCanceled cancels the order on behalf of the party in the event.
+// Cancellation which is never going to succeed (unknown party, too long reason,
+// cancellation forbidden by policy or of a closed order) is acked and logged.
 func (h *Handler) OrderCanceled(msg *message.Message) error {
 	var (
 		ctx = msg.Context()
@@ -25,16 +31,42 @@ func (h *Handler) OrderCanceled(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order canceled event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderCanceled.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	canceledBy := order.ActorSystem
+	if eventOrderCanceled.CanceledBy != "" {
+		canceledBy, err = order.ParseActor(eventOrderCanceled.CanceledBy)
+		if err != nil {
+			h.logger.Error().
+				Err(err).
+				Str("order-id", eventOrderCanceled.OrderID.String()).
+				Msg("order is not canceled")
+
+			return nil
+		}
+	}
+
+	err = h.operate(ctx, msg, eventOrderCanceled.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(canceledBy),
+			order.WithCausationID(msg.UUID),
+		)
 
 		canceled, cancelErr := stateOperator.CancelOrder(eventOrderCanceled.Reason)
-		if cancelErr != nil || !canceled {
+		switch {
+		case errors.Is(cancelErr, order.ErrCancellationForbidden),
+			errors.Is(cancelErr, order.ErrCancellationReasonTooLong),
+			errors.Is(cancelErr, order.ErrOrderClosed):
+			// redelivery never cancels such order, so message is acked
+			h.logger.Error().
+				Err(cancelErr).
+				Str("order-id", o.ID().String()).
+				Str("canceled-by", canceledBy.String()).
+				Msg("order is not canceled")
+
+			return nil
+		case cancelErr != nil || !canceled:
 			return errors.Wrapf(cancelErr, "can`t set order`s state to canceled: order: %s", o.ID())
 		}
 
-		// process eventOrderCanceled.Reason
-
 		return nil
 	})
 	if err != nil {
@@ -43,3 +75,18 @@ func (h *Handler) OrderCanceled(msg *message.Message) error {
 
 	return nil
 }
+
+// OrderCanceledRelay relays cancellations made by order service and written into the outbox, see [pubsub.Canceled].
+func (h *Handler) OrderCanceledRelay(msg *message.Message) ([]*message.Message, error) {
+	_, span := pubsub.SpanFromMessage(
+		msg,
+		"consumer.order.canceled.relay",
+		"order.canceled relay handler",
+		nil,
+	)
+	defer span.End()
+
+	h.logger.Info().Str("msg-id", msg.UUID).Msg("Received message from outbox topic Canceled")
+
+	return []*message.Message{msg}, nil
+}
@@ -19,8 +19,11 @@ func (h *Handler) OrderClosed(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order closed event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderClosed.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderClosed.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorSystem),
+			order.WithCausationID(msg.UUID),
+		)
 
 		closed, closeErr := stateOperator.CloseOrder()
 		if closeErr != nil || !closed {
@@ -19,8 +19,11 @@ func (h *Handler) CookingOrder(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order cooking event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderCooking.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderCooking.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorRestaurant),
+			order.WithCausationID(msg.UUID),
+		)
 
 		cooking, cookErr := stateOperator.CookOrder()
 		if cookErr != nil || !cooking {
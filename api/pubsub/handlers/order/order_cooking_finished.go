@@ -19,8 +19,11 @@ func (h *Handler) FinishedCooking(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order finished cooking event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderFinished.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderFinished.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorRestaurant),
+			order.WithCausationID(msg.UUID),
+		)
 
 		finishedCooking, finishedErr := stateOperator.OrderFinished()
 		if finishedErr != nil || !finishedCooking {
@@ -0,0 +1,43 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) CourierReassigned(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventCourierReassigned := &event.JSONCourierReassigned{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventCourierReassigned)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse courier reassigned event")
+	}
+
+	err = h.operate(ctx, msg, eventCourierReassigned.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorSystem),
+			order.WithCausationID(msg.UUID),
+		)
+
+		reassigned, reassignErr := stateOperator.ReassignCourier(
+			eventCourierReassigned.CourierID,
+			eventCourierReassigned.Reason,
+		)
+		if reassignErr != nil || !reassigned {
+			return errors.Wrapf(reassignErr, "can`t reassign courier: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update courier reassigned")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) CourierReleased(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventCourierReleased := &event.JSONCourierReleased{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventCourierReleased)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse courier released event")
+	}
+
+	err = h.operate(ctx, msg, eventCourierReleased.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorCourier),
+			order.WithCausationID(msg.UUID),
+		)
+
+		released, releaseErr := stateOperator.ReleaseCourier(
+			eventCourierReleased.CourierID,
+			eventCourierReleased.Reason,
+		)
+		if releaseErr != nil || !released {
+			return errors.Wrapf(releaseErr, "can`t release courier: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update courier released")
+	}
+
+	return nil
+}
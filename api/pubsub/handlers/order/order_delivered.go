@@ -19,8 +19,11 @@ func (h *Handler) OrderDelivered(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order delivered event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderDelivered.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderDelivered.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorCourier),
+			order.WithCausationID(msg.UUID),
+		)
 
 		delivered, deliveredErr := stateOperator.OrderDelivered()
 		if deliveredErr != nil || !delivered {
@@ -19,8 +19,11 @@ func (h *Handler) OrderDelivering(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order delivering event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderDelivering.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderDelivering.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorCourier),
+			order.WithCausationID(msg.UUID),
+		)
 
 		delivering, deliveryErr := stateOperator.DeliveringOrder()
 		if deliveryErr != nil || !delivering {
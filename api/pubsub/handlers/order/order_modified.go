@@ -0,0 +1,20 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"service/pubsub"
+)
+
+func (h *Handler) OrderModified(msg *message.Message) ([]*message.Message, error) {
+	_, span := pubsub.SpanFromMessage(
+		msg,
+		"consumer.order.modified",
+		"order.modified handler",
+		nil,
+	)
+	defer span.End()
+
+	h.logger.Info().Str("msg-id", msg.UUID).Msg("Received message from topic OrderModified")
+
+	return []*message.Message{msg}, nil
+}
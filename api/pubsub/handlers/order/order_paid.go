@@ -5,10 +5,14 @@ import (
 	"github.com/pkg/errors"
 	"service/domain/order"
 	"service/domain/order/event"
+	"service/domain/shared/money"
 )
 
 var _ message.NoPublishHandlerFunc = ((*Handler)(nil)).OrderPaid
 
+// OrderPaid records payment of the order. Order is considered charged its total if the event has no amount.
+// Payment which arrives after order has been canceled is refunded,
+// payment of a closed order or in another currency is acked and logged.
 func (h *Handler) OrderPaid(msg *message.Message) error {
 	var (
 		ctx = msg.Context()
@@ -21,11 +25,41 @@ func (h *Handler) OrderPaid(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order paid event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderPaid.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	var charged *money.Money
 
-		orderPaid, payErr := stateOperator.PayOrder(eventOrderPaid.OrderID)
-		if payErr != nil || !orderPaid {
+	if eventOrderPaid.Amount != nil {
+		amount, amountErr := eventOrderPaid.Amount.ToMoney()
+		if amountErr != nil {
+			return errors.Wrap(amountErr, "failed to parse order paid amount")
+		}
+
+		charged = &amount
+	}
+
+	err = h.operate(ctx, msg, eventOrderPaid.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorPayment),
+			order.WithCausationID(msg.UUID),
+		)
+
+		// order is considered charged its total if producer does not tell the amount
+		amount := o.Total()
+		if charged != nil {
+			amount = *charged
+		}
+
+		orderPaid, payErr := stateOperator.PayOrder(eventOrderPaid.TransactionID, amount)
+		switch {
+		case errors.Is(payErr, order.ErrOrderClosed), errors.Is(payErr, money.ErrCurrencyMismatch):
+			// redelivery never records such payment, so message is acked and payment is left for manual refund
+			h.logger.Error().
+				Err(payErr).
+				Str("order-id", o.ID().String()).
+				Str("transaction-id", eventOrderPaid.TransactionID.String()).
+				Msg("payment is not recorded")
+
+			return nil
+		case payErr != nil || !orderPaid:
 			return errors.Wrapf(payErr, "can`t set order`s state to paid: order: %s", o.ID())
 		}
 
@@ -0,0 +1,40 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) OrderPickedUp(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventOrderPickedUp := &event.JSONPickedUp{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventOrderPickedUp)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse order picked up event")
+	}
+
+	err = h.operate(ctx, msg, eventOrderPickedUp.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorRestaurant),
+			order.WithCausationID(msg.UUID),
+		)
+
+		pickedUp, pickUpErr := stateOperator.OrderPickedUp()
+		if pickUpErr != nil || !pickedUp {
+			return errors.Wrapf(pickUpErr, "can`t set order`s state to picked up: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update order picked up")
+	}
+
+	return nil
+}
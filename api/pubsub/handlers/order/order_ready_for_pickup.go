@@ -0,0 +1,40 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) OrderReadyForPickup(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventOrderReadyForPickup := &event.JSONReadyForPickup{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventOrderReadyForPickup)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse order ready for pickup event")
+	}
+
+	err = h.operate(ctx, msg, eventOrderReadyForPickup.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorRestaurant),
+			order.WithCausationID(msg.UUID),
+		)
+
+		ready, readyErr := stateOperator.OrderReadyForPickup()
+		if readyErr != nil || !ready {
+			return errors.Wrapf(readyErr, "can`t set order`s state to ready for pickup: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update order ready for pickup")
+	}
+
+	return nil
+}
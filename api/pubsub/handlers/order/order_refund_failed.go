@@ -0,0 +1,40 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) OrderRefundFailed(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventOrderRefundFailed := &event.JSONRefundFailed{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventOrderRefundFailed)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse order refund failed event")
+	}
+
+	err = h.operate(ctx, msg, eventOrderRefundFailed.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorPayment),
+			order.WithCausationID(msg.UUID),
+		)
+
+		failed, failErr := stateOperator.FailRefund(eventOrderRefundFailed.Reason)
+		if failErr != nil || !failed {
+			return errors.Wrapf(failErr, "can`t set order`s state to refund failed: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update order refund failed")
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/domain/order/event"
+)
+
+func (h *Handler) OrderRefunded(msg *message.Message) error {
+	var (
+		ctx = msg.Context()
+	)
+
+	eventOrderRefunded := &event.JSONRefunded{}
+
+	err := h.unmarshaler.Unmarshal(msg.Payload, eventOrderRefunded)
+	if err != nil {
+		return errors.Wrap(err, "failed to parse order refunded event")
+	}
+
+	err = h.operate(ctx, msg, eventOrderRefunded.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorPayment),
+			order.WithCausationID(msg.UUID),
+		)
+
+		refunded, refundErr := stateOperator.RefundOrder(eventOrderRefunded.RefundID)
+		if refundErr != nil || !refunded {
+			return errors.Wrapf(refundErr, "can`t set order`s state to refunded: order: %s", o.ID())
+		}
+
+		return nil
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to update order refunded")
+	}
+
+	return nil
+}
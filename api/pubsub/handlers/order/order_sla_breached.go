@@ -0,0 +1,21 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"service/pubsub"
+)
+
+// OrderSLABreached relays SLA breaches written into the outbox.
+func (h *Handler) OrderSLABreached(msg *message.Message) ([]*message.Message, error) {
+	_, span := pubsub.SpanFromMessage(
+		msg,
+		"consumer.order.sla.breached",
+		"order.sla.breached handler",
+		nil,
+	)
+	defer span.End()
+
+	h.logger.Info().Str("msg-id", msg.UUID).Msg("Received message from topic SLABreached")
+
+	return []*message.Message{msg}, nil
+}
@@ -0,0 +1,21 @@
+package order
+
+import (
+	"github.com/ThreeDotsLabs/watermill/message"
+	"service/pubsub"
+)
+
+// OrderStatusChanged relays order state transitions written into the outbox.
+func (h *Handler) OrderStatusChanged(msg *message.Message) ([]*message.Message, error) {
+	_, span := pubsub.SpanFromMessage(
+		msg,
+		"consumer.order.status.changed",
+		"order.status.changed handler",
+		nil,
+	)
+	defer span.End()
+
+	h.logger.Info().Str("msg-id", msg.UUID).Msg("Received message from topic StatusChanged")
+
+	return []*message.Message{msg}, nil
+}
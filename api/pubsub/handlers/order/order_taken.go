@@ -19,8 +19,11 @@ func (h *Handler) CookingTaken(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order taken event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderTaken.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderTaken.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorCourier),
+			order.WithCausationID(msg.UUID),
+		)
 
 		taken, takingErr := stateOperator.CourierTookOrder(eventOrderTaken.CourierID)
 		if takingErr != nil || !taken {
@@ -19,8 +19,11 @@ func (h *Handler) OrderWaitingForCourier(msg *message.Message) error {
 		return errors.Wrap(err, "failed to parse order waiting event")
 	}
 
-	err = h.repository.Operate(ctx, eventOrderWaiting.OrderID, func(o *order.Order) error {
-		stateOperator := order.NewStateOperator(o)
+	err = h.operate(ctx, msg, eventOrderWaiting.OrderID, func(o *order.Order) error {
+		stateOperator := order.NewStateOperator(o,
+			order.WithActor(order.ActorRestaurant),
+			order.WithCausationID(msg.UUID),
+		)
 
 		waiting, waitingErr := stateOperator.WaitForCourier()
 		if waitingErr != nil || !waiting {
@@ -8,6 +8,7 @@ import (
 	"github.com/go-feast/topics"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/zerolog"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
@@ -17,17 +18,23 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	jobs "service/api/jobs/order"
 	"service/api/pubsub/handlers/order"
 	"service/closer"
 	"service/config"
+	domain "service/domain/order"
 	"service/event"
 	mw "service/http/middleware"
+	"service/infrastructure/outbox"
+	idempotencyrepository "service/infrastructure/repositories/idempotency/gorm"
+	orderrepository "service/infrastructure/repositories/order"
 	repository "service/infrastructure/repositories/order/gorm"
 	"service/logging"
 	"service/metrics"
 	"service/pubsub"
 	serv "service/server"
 	"service/tracing"
+	"service/worker"
 )
 
 const (
@@ -107,7 +114,19 @@ func main() {
 			Msg("failed to connect to database")
 	}
 
-	closers := RegisterConsumerHandlers(router, db, c.Kafka)
+	// orders table is queried directly whatever order store is
+	queries := repository.NewOrderRepository(db)
+
+	orderRepository, err := orderrepository.NewOrderRepository(db, c.DB)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create order repository")
+	}
+
+	closers := RegisterConsumerHandlers(router, db, orderRepository, c.Kafka)
+
+	Closer.AppendClosers(closers...)
+
+	closers = RegisterJobs(ctx, db, orderRepository, queries, c.Jobs, logger)
 
 	Closer.AppendClosers(closers...)
 
@@ -136,7 +155,12 @@ func RegisterMetricRoute(r chi.Router) {
 	r.Get("/healthz", mw.Healthz)
 }
 
-func RegisterConsumerHandlers(r *message.Router, db *gorm.DB, c *config.KafkaConfig) []closer.C {
+func RegisterConsumerHandlers(
+	r *message.Router,
+	db *gorm.DB,
+	orderRepository order.Repository,
+	c *config.KafkaConfig,
+) []closer.C {
 	subscriberSQL, err := pubsub.NewSQLSubscriber(db, logging.NewWatermillAdapter())
 	if err != nil {
 		panic(err)
@@ -152,8 +176,6 @@ func RegisterConsumerHandlers(r *message.Router, db *gorm.DB, c *config.KafkaCon
 		panic(err)
 	}
 
-	orderRepository := repository.NewOrderRepository(db)
-
 	handler := order.NewHandler(
 		logging.New(),
 		event.JSONMarshaler{},
@@ -170,6 +192,42 @@ func RegisterConsumerHandlers(r *message.Router, db *gorm.DB, c *config.KafkaCon
 		handler.OrderCreated,
 	)
 
+	r.AddHandler(
+		"handler.order.modified",
+		pubsub.Modified.String(),
+		subscriberSQL,
+		pubsub.Modified.String(),
+		publisherKafka,
+		handler.OrderModified,
+	)
+
+	r.AddHandler(
+		"handler.order.status.changed",
+		pubsub.StatusChanged.String(),
+		subscriberSQL,
+		pubsub.StatusChanged.String(),
+		publisherKafka,
+		handler.OrderStatusChanged,
+	)
+
+	r.AddHandler(
+		"handler.order.sla.breached",
+		pubsub.SLABreached.String(),
+		subscriberSQL,
+		pubsub.SLABreached.String(),
+		publisherKafka,
+		handler.OrderSLABreached,
+	)
+
+	r.AddHandler(
+		"handler.order.canceled.relay",
+		pubsub.Canceled.String(),
+		subscriberSQL,
+		pubsub.Canceled.String(),
+		publisherKafka,
+		handler.OrderCanceledRelay,
+	)
+
 	registerOrderStateHandlers(r, handler, subscriberKafka)
 
 	return []closer.C{
@@ -179,6 +237,57 @@ func RegisterConsumerHandlers(r *message.Router, db *gorm.DB, c *config.KafkaCon
 	}
 }
 
+// RegisterJobs runs background jobs until ctx is done.
+func RegisterJobs(
+	ctx context.Context,
+	db *gorm.DB,
+	orderRepository outbox.Repository,
+	queries *repository.OrderRepository,
+	c *config.JobsConfig,
+	logger *zerolog.Logger,
+) []closer.C { //nolint:unparam
+	orderOutbox := outbox.NewOutbox(orderRepository, event.JSONMarshaler{})
+
+	handler := jobs.NewHandler(
+		logger,
+		queries,
+		orderOutbox,
+		c.ReleaseBatch,
+	)
+
+	go worker.Run(ctx, "order.release.scheduled", c.ReleaseInterval, handler.ReleaseScheduled, logger)
+
+	inboxHandler := jobs.NewInboxHandler(logger, queries, c.InboxRetention)
+
+	go worker.Run(ctx, "order.inbox.cleanup", c.InboxCleanupInterval, inboxHandler.CleanupInbox, logger)
+
+	idempotencyrepository.InitializeIdempotencyScheme(db)
+
+	idempotencyHandler := jobs.NewIdempotencyHandler(logger, idempotencyrepository.NewKeyRepository(db), c.IdempotencyRetention)
+
+	go worker.Run(ctx, "order.idempotency.cleanup", c.IdempotencyCleanupInterval, idempotencyHandler.CleanupIdempotencyKeys, logger)
+
+	paymentTimeoutHandler := jobs.NewPaymentTimeoutHandler(logger, queries, orderOutbox, c.PaymentTTL, c.PaymentTimeoutBatch)
+
+	go worker.Run(ctx, "order.payment.timeout", c.PaymentTimeoutInterval, paymentTimeoutHandler.CancelUnpaid, logger)
+
+	sla, err := domain.NewSLA(c.SLA)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid order sla")
+	}
+
+	slaHandler := jobs.NewSLAHandler(
+		logger,
+		repository.NewSLARepository(db, outbox.NewBreachWriter(event.JSONMarshaler{})),
+		sla,
+		c.SLABatch,
+	)
+
+	go worker.Run(ctx, "order.sla.monitor", c.SLAInterval, slaHandler.MonitorSLA, logger)
+
+	return nil
+}
+
 func registerOrderStateHandlers(r *message.Router, handler *order.Handler, subKafka message.Subscriber) {
 	r.AddNoPublisherHandler(
 		"handler.order.paid",
@@ -242,4 +351,46 @@ func registerOrderStateHandlers(r *message.Router, handler *order.Handler, subKa
 		subKafka,
 		handler.OrderCanceled,
 	)
+
+	r.AddNoPublisherHandler(
+		"order.courier.released",
+		pubsub.CourierReleased.String(),
+		subKafka,
+		handler.CourierReleased,
+	)
+
+	r.AddNoPublisherHandler(
+		"order.courier.reassigned",
+		pubsub.CourierReassigned.String(),
+		subKafka,
+		handler.CourierReassigned,
+	)
+
+	r.AddNoPublisherHandler(
+		"order.pickup.ready",
+		pubsub.ReadyForPickup.String(),
+		subKafka,
+		handler.OrderReadyForPickup,
+	)
+
+	r.AddNoPublisherHandler(
+		"order.picked.up",
+		pubsub.PickedUp.String(),
+		subKafka,
+		handler.OrderPickedUp,
+	)
+
+	r.AddNoPublisherHandler(
+		"order.refunded",
+		pubsub.Refunded.String(),
+		subKafka,
+		handler.OrderRefunded,
+	)
+
+	r.AddNoPublisherHandler(
+		"order.refund.failed",
+		pubsub.RefundFailed.String(),
+		subKafka,
+		handler.OrderRefundFailed,
+	)
 }
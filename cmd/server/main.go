@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	_ "github.com/jackc/pgx/v5/stdlib"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/redis/go-redis/v9"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/sdk/resource"
 	semconv "go.opentelemetry.io/otel/semconv/v1.25.0"
@@ -16,17 +18,24 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"service/api/http/handlers/admin"
 	"service/api/http/handlers/order"
 	"service/closer"
 	"service/config"
 	domain "service/domain/order"
+	"service/domain/shared/zone"
 	"service/event"
 	mw "service/http/middleware"
+	"service/infrastructure/notify"
 	"service/infrastructure/outbox"
+	idempotencyrepository "service/infrastructure/repositories/idempotency/gorm"
+	orderrepository "service/infrastructure/repositories/order"
 	repository "service/infrastructure/repositories/order/gorm"
+	"service/infrastructure/repositories/zone/geojson"
+	zonerepository "service/infrastructure/repositories/zone/gorm"
+	"service/infrastructure/tracking"
 	"service/logging"
 	"service/metrics"
-	"service/pubsub"
 	serv "service/server"
 	"service/tracing"
 )
@@ -93,6 +102,11 @@ func main() {
 	db = db.WithContext(ctx)
 
 	domain.InitializeOrderScheme(db)
+	repository.InitializeInboxScheme(db)
+	repository.InitializeSLAScheme(db)
+	repository.InitializeQueryIndexes(db)
+	idempotencyrepository.InitializeIdempotencyScheme(db)
+	repository.InitializeStateChangeNotifications(db)
 
 	// main server
 	mainServiceServer, mainRouter := serv.NewServer(c.Server)
@@ -102,7 +116,44 @@ func main() {
 
 	// register routes
 	//		main
-	fc := RegisterMainServiceRoutes(mainRouter, db)
+	zones, err := NewZoneProvider(db, c.Zones)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to load delivery zones")
+		return
+	}
+
+	orderRepository, err := orderrepository.NewOrderRepository(db, c.DB)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("failed to create order repository")
+		return
+	}
+
+	changes := notify.NewBroker()
+
+	// order event streams are ended when server shuts down, otherwise shutdown would wait for them
+	mainServiceServer.RegisterOnShutdown(changes.Close)
+
+	go notify.NewListener(c.DB.DSN(), repository.StateChangesChannel, changes, logger).Run(ctx)
+
+	redisOptions, err := redis.ParseURL(c.Redis.RedisURL)
+	if err != nil {
+		logger.Fatal().Err(err).Msg("invalid redis url")
+		return
+	}
+
+	redisClient := redis.NewClient(redisOptions)
+
+	forClose.AppendClosers(closer.C{Name: "redis", Closer: redisClient})
+
+	fc := RegisterMainServiceRoutes(
+		mainRouter,
+		db,
+		orderRepository,
+		zones,
+		changes,
+		tracking.NewRedisTracker(redisClient),
+		c.Server.AllowedOrigins,
+	)
 
 	forClose.AppendClosers(fc...)
 	//		metric
@@ -127,19 +178,17 @@ func Middlewares(r chi.Router) {
 func RegisterMainServiceRoutes(
 	r chi.Router,
 	db *gorm.DB,
+	orderRepository outbox.Repository,
+	zones zone.Provider,
+	changes order.ChangeSubscriber,
+	locations domain.LocationTracker,
+	allowedOrigins []string,
 ) []closer.C { //nolint:unparam
 	// middlewares
 	Middlewares(r)
 	r.Get("/healthz", mw.Healthz)
 
-	publisher, err := pubsub.NewSQLPublisher(db, logging.NewWatermillAdapter())
-	if err != nil {
-		panic("Failed to create publisher")
-	}
-
-	orderRepository := repository.NewOrderRepository(db)
-	saver := outbox.NewOutbox(
-		publisher,
+	orderOutbox := outbox.NewOutbox(
 		orderRepository,
 		event.JSONMarshaler{},
 	)
@@ -147,18 +196,53 @@ func RegisterMainServiceRoutes(
 	handler := order.NewHandler(
 		otel.GetTracerProvider().Tracer(serviceName),
 		orderRepository,
-		saver,
+		repository.NewOrderRepository(db),
+		orderOutbox,
+		zones,
+		idempotencyrepository.NewKeyRepository(db),
+		idempotencyrepository.NewOrderSaver(orderOutbox),
+		changes,
+		locations,
+		allowedOrigins,
 	)
 
+	adminHandler := admin.NewHandler(repository.NewSLARepository(db, nil))
+
 	r.With(mw.ResolveTraceIDInHTTP(serviceName)).
 		Route("/api/v1", func(r chi.Router) {
+			r.Get("/orders", handler.ListOrders)
+
 			r.Route("/order", func(r chi.Router) {
 				r.Post("/", handler.TakeOrder)
+				r.Get("/{uuid}", handler.GetOrder)
+				r.Patch("/{uuid}", handler.ModifyOrder)
+				r.Post("/{uuid}/cancel", handler.CancelOrder)
+				r.Get("/{uuid}/history", handler.GetOrderHistory)
+				r.Get("/{uuid}/events", handler.OrderEvents)
+				r.Post("/{uuid}/courier/location", handler.TrackCourier)
+				r.Get("/{uuid}/courier/location/ws", handler.CourierLocations)
+			})
+
+			r.Route("/admin", func(r chi.Router) {
+				r.Get("/sla/breaches", adminHandler.ListSLABreaches)
+				r.Post("/order/{uuid}/cancel", handler.CancelOrderBySupport)
 			})
 		})
 
-	return []closer.C{
-		{Name: "pub", Closer: publisher},
+	return nil
+}
+
+// NewZoneProvider creates delivery zones provider from configured source.
+func NewZoneProvider(db *gorm.DB, c *config.ZonesConfig) (zone.Provider, error) {
+	switch c.Source {
+	case config.ZonesSourceDatabase:
+		zonerepository.InitializeZoneScheme(db)
+
+		return zonerepository.NewZoneRepository(db), nil
+	case config.ZonesSourceFile:
+		return geojson.Load(c.File)
+	default:
+		return nil, fmt.Errorf("unknown delivery zones source: %s", c.Source)
 	}
 }
 
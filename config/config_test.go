@@ -76,3 +76,120 @@ func TestMetricServerConfig(t *testing.T) {
 	assert.Equal(t, c.ReadTimeoutDur(), 10*time.Second)
 	assert.Equal(t, c.IdleTimeoutDur(), 10*time.Second)
 }
+
+func TestJobsConfig_Validate(t *testing.T) {
+	valid := func() config.JobsConfig {
+		return config.JobsConfig{
+			ReleaseInterval:            time.Second,
+			ReleaseBatch:               1,
+			InboxRetention:             time.Hour,
+			InboxCleanupInterval:       time.Second,
+			IdempotencyRetention:       time.Hour,
+			IdempotencyCleanupInterval: time.Second,
+			PaymentTTL:                 time.Minute,
+			PaymentTimeoutInterval:     time.Second,
+			PaymentTimeoutBatch:        1,
+			SLAInterval:                time.Second,
+			SLABatch:                   1,
+		}
+	}
+
+	t.Run("assert valid config passes", func(t *testing.T) {
+		c := valid()
+		assert.NoError(t, c.Validate())
+	})
+
+	t.Run("assert non positive interval is rejected", func(t *testing.T) {
+		c := valid()
+		c.ReleaseInterval = 0
+		c.SLAInterval = -time.Second
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidInterval)
+		assert.ErrorContains(t, err, "RELEASE_INTERVAL")
+		assert.ErrorContains(t, err, "SLA_INTERVAL")
+	})
+
+	t.Run("assert non positive batch is rejected", func(t *testing.T) {
+		c := valid()
+		c.PaymentTimeoutBatch = 0
+
+		assert.ErrorIs(t, c.Validate(), config.ErrInvalidBatch)
+	})
+
+	t.Run("assert non positive payment ttl is rejected", func(t *testing.T) {
+		c := valid()
+		c.PaymentTTL = 0
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidDuration)
+		assert.ErrorContains(t, err, "PAYMENT_TTL")
+	})
+
+	t.Run("assert non positive inbox retention is rejected", func(t *testing.T) {
+		c := valid()
+		c.InboxRetention = -time.Hour
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidDuration)
+		assert.ErrorContains(t, err, "INBOX_RETENTION")
+	})
+
+	t.Run("assert non positive idempotency retention is rejected", func(t *testing.T) {
+		c := valid()
+		c.IdempotencyRetention = 0
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidDuration)
+		assert.ErrorContains(t, err, "IDEMPOTENCY_RETENTION")
+	})
+
+	t.Run("assert consumer config is validated when parsed", func(t *testing.T) {
+		for key, value := range map[string]string{
+			"POSTGRES_HOST":         "localhost:5432",
+			"POSTGRES_USER":         "user",
+			"POSTGRES_PASSWORD":     "password",
+			"POSTGRES_DB":           "db",
+			"POSTGRES_SSL":          "disable",
+			"REDIS_URL":             "redis://localhost:6379/0",
+			"KAFKA_URL":             "localhost:9092",
+			"METRICS_PORT":          "9090",
+			"METRICS_HOST":          "localhost",
+			"ENVIRONMENT":           "testing",
+			"JOBS_RELEASE_INTERVAL": "0s",
+		} {
+			t.Setenv(key, value)
+		}
+
+		err := config.ParseConfig(&config.ConsumerConfig{})
+		assert.ErrorIs(t, err, config.ErrInvalidInterval)
+
+		t.Setenv("JOBS_RELEASE_INTERVAL", "30s")
+
+		assert.NoError(t, config.ParseConfig(&config.ConsumerConfig{}))
+	})
+}
+
+func TestDBConfig_Validate(t *testing.T) {
+	t.Run("assert negative operate retries are rejected", func(t *testing.T) {
+		c := config.DBConfig{OperateRetries: -1}
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidRetries)
+		assert.ErrorContains(t, err, "OPERATE_RETRIES")
+	})
+
+	t.Run("assert negative snapshot frequency is rejected", func(t *testing.T) {
+		c := config.DBConfig{SnapshotEvery: -1}
+
+		err := c.Validate()
+		assert.ErrorIs(t, err, config.ErrInvalidSnapshot)
+		assert.ErrorContains(t, err, "SNAPSHOT_EVERY")
+	})
+
+	t.Run("assert zero operate retries and snapshot frequency pass", func(t *testing.T) {
+		c := config.DBConfig{}
+
+		assert.NoError(t, c.Validate())
+	})
+}
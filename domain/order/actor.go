@@ -0,0 +1,50 @@
+package order
+
+import "github.com/pkg/errors"
+
+var ErrInvalidActor = errors.New("invalid actor")
+
+// Actor represents a party that changes the Order.
+type Actor string
+
+func (a Actor) String() string { return string(a) }
+
+const (
+	// ActorSystem represents order service itself or any automated process.
+	ActorSystem Actor = "system"
+
+	// ActorCustomer represents a customer who placed the Order.
+	ActorCustomer Actor = "customer"
+
+	// ActorRestaurant represents a restaurant that cooks the Order.
+	ActorRestaurant Actor = "restaurant"
+
+	// ActorCourier represents a courier who delivers the Order.
+	ActorCourier Actor = "courier"
+
+	// ActorPayment represents a payment provider.
+	ActorPayment Actor = "payment"
+
+	// ActorSupport represents a support agent acting on behalf of the service.
+	ActorSupport Actor = "support"
+)
+
+// actors is a map of actor names and actors.
+var actors = map[string]Actor{
+	"system":     ActorSystem,
+	"customer":   ActorCustomer,
+	"restaurant": ActorRestaurant,
+	"courier":    ActorCourier,
+	"payment":    ActorPayment,
+	"support":    ActorSupport,
+}
+
+// ParseActor returns Actor by its name.
+func ParseActor(name string) (Actor, error) {
+	actor, ok := actors[name]
+	if !ok {
+		return "", errors.Wrapf(ErrInvalidActor, "unknown actor: %s", name)
+	}
+
+	return actor, nil
+}
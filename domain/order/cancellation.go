@@ -0,0 +1,79 @@
+package order
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+// MaxCancellationReasonLength is the maximum length of a cancellation reason.
+const MaxCancellationReasonLength = 512
+
+// ReasonPaymentTimeout is a reason Order is canceled with if it has not been paid in time.
+const ReasonPaymentTimeout = "payment_timeout"
+
+var (
+	ErrCancellationForbidden     = errors.New("cancellation forbidden")
+	ErrCancellationReasonTooLong = errors.Errorf("cancellation reason is too long: must be at most %d characters",
+		MaxCancellationReasonLength)
+)
+
+// Cancellation describes why, when and by whom Order has been canceled.
+// Cancellation is a value object. Zero Cancellation means Order has not been canceled.
+type Cancellation struct {
+	// reason states for a free-text explanation of the cancellation.
+	reason string
+
+	// by states for a party that canceled the Order.
+	by Actor
+
+	// at represents when Order has been canceled.
+	at time.Time
+}
+
+func (c Cancellation) Reason() string { return c.reason }
+func (c Cancellation) By() Actor      { return c.by }
+func (c Cancellation) At() time.Time  { return c.at }
+
+// IsZero shows if Cancellation is empty.
+func (c Cancellation) IsZero() bool {
+	return c.by == "" && c.at.IsZero()
+}
+
+// CancellationPolicy decides if Actor may cancel Order in its current state.
+type CancellationPolicy interface {
+	CanCancel(o *Order, by Actor) error
+}
+
+// StatePolicy is a CancellationPolicy that allows each Actor to cancel Order only in listed states.
+// Actors which are not listed cannot cancel Order at all.
+type StatePolicy map[Actor][]State
+
+func (p StatePolicy) CanCancel(o *Order, by Actor) error {
+	for _, state := range p[by] {
+		if o.Is(state) {
+			return nil
+		}
+	}
+
+	return errors.Wrapf(ErrCancellationForbidden, "%s cannot cancel order in state %s", by, o.state)
+}
+
+// DefaultCancellationPolicy describes who may cancel Order in which state:
+//   - customer until courier starts delivering;
+//   - restaurant until the meals are ready or until pickup order is picked up;
+//   - courier since Order is waiting for a courier until it is delivered;
+//   - system and support agent at any moment before Order is closed.
+var DefaultCancellationPolicy = StatePolicy{
+	ActorCustomer:   {Scheduled, Created, Paid, Cooking, Finished, WaitingForCourier, CourierTook},
+	ActorRestaurant: {Scheduled, Created, Paid, Cooking, Finished, ReadyForPickup},
+	ActorCourier:    {WaitingForCourier, CourierTook, Delivering},
+	ActorSystem:     anyOpenState,
+	ActorSupport:    anyOpenState,
+}
+
+// anyOpenState lists every State Order can be canceled in.
+var anyOpenState = []State{
+	Scheduled, Created, Paid, Cooking, Finished,
+	WaitingForCourier, CourierTook, Delivering, Delivered,
+	ReadyForPickup, PickedUp,
+}
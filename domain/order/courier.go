@@ -0,0 +1,58 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"time"
+)
+
+var ErrCourierNotAssigned = errors.New("courier is not assigned to order")
+
+// CourierAssignment represents a period a courier has been assigned to the Order.
+// CourierAssignment is a value object.
+type CourierAssignment struct {
+	// id states for CourierAssignment [uuid].
+	id uuid.UUID
+
+	// courierID states for assigned courier [uuid].
+	courierID uuid.UUID
+
+	// assignedAt represents when courier has been assigned to the Order.
+	assignedAt time.Time
+
+	// releasedAt represents when courier has been released from the Order.
+	// It is zero while courier is assigned.
+	releasedAt time.Time
+
+	// reason states for why courier has been released or replaced.
+	reason string
+}
+
+func (a CourierAssignment) ID() uuid.UUID         { return a.id }
+func (a CourierAssignment) CourierID() uuid.UUID  { return a.courierID }
+func (a CourierAssignment) AssignedAt() time.Time { return a.assignedAt }
+func (a CourierAssignment) ReleasedAt() time.Time { return a.releasedAt }
+func (a CourierAssignment) Reason() string        { return a.reason }
+
+// Active shows if courier is still assigned to the Order.
+func (a CourierAssignment) Active() bool {
+	return a.releasedAt.IsZero()
+}
+
+func newCourierAssignment(courierID uuid.UUID) CourierAssignment {
+	return CourierAssignment{
+		id:         uuid.New(),
+		courierID:  courierID,
+		assignedAt: time.Now(),
+	}
+}
+
+// assignCourier assigns courier to the Order and records the assignment.
+func (o *Order) assignCourier(courierID uuid.UUID) {
+	o.record(CourierAssigned{Assignment: assignmentToDTO(o.id, newCourierAssignment(courierID))})
+}
+
+// releaseCourier releases current courier from the Order.
+func (o *Order) releaseCourier(reason string) {
+	o.record(CourierUnassigned{Reason: reason, At: time.Now()})
+}
@@ -5,11 +5,13 @@ import (
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
 	"service/domain/shared/destination"
+	"service/domain/shared/money"
 	"time"
 )
 
 func InitializeOrderScheme(db *gorm.DB) {
-	err := db.AutoMigrate(&DatabaseOrderDTO{}, &RestaurantOrderDTO{}, &Meal{})
+	err := db.AutoMigrate(&DatabaseOrderDTO{}, &RestaurantOrderDTO{}, &Meal{}, &ItemDTO{}, &StateChangeDTO{},
+		&CourierAssignmentDTO{})
 	if err != nil {
 		panic(errors.Wrap(err, "failed to migrate database"))
 	}
@@ -23,9 +25,48 @@ type DatabaseOrderDTO struct { //nolint:govet
 	CourierID     uuid.UUID          `gorm:"type:uuid"`
 	State         State              `gorm:"type:text"`
 	TransactionID uuid.UUID          `gorm:"type:uuid"`
-	Latitude      float64            `gorm:"type:numeric"`
-	Longitude     float64            `gorm:"type:numeric"`
+	CancelReason  string             `gorm:"type:text"`
+	CanceledBy    Actor              `gorm:"type:text"`
+	CanceledAt    *time.Time
+	RefundID      uuid.UUID `gorm:"type:uuid"`
+	RefundFailure string    `gorm:"type:text"`
+	Currency      string    `gorm:"type:char(3)"`
+	Subtotal      int64
+	DeliveryFee   int64
+	ServiceFee    int64
+	Tax           int64
+	Tip           int64
+	Discount      int64
+	Total         int64
+	PaidAmount    int64
+	PaidCurrency  string      `gorm:"type:char(3)"`
+	Fulfillment   Fulfillment `gorm:"type:text;default:delivery"`
+	Latitude      float64     `gorm:"type:numeric"`
+	Longitude     float64     `gorm:"type:numeric"`
+	Address       AddressDTO  `gorm:"embedded;embeddedPrefix:address_"`
+	RestaurantLat float64     `gorm:"type:numeric"`
+	RestaurantLng float64     `gorm:"type:numeric"`
+	ETA           *time.Time
+	Items         []ItemDTO              `gorm:"foreignKey:OrderID;references:ID"`
+	History       []StateChangeDTO       `gorm:"foreignKey:OrderID;references:ID"`
+	Assignments   []CourierAssignmentDTO `gorm:"foreignKey:OrderID;references:ID"`
+	DeliverAt     *time.Time
+	ReleaseAt     *time.Time `gorm:"index"`
 	CreatedAt     time.Time
+	Version       int64 `gorm:"not null;default:0"`
+}
+
+// AddressDTO represents order destination address.
+type AddressDTO struct { //nolint:govet
+	Line1        string `gorm:"type:text"`
+	Line2        string `gorm:"type:text"`
+	City         string `gorm:"type:text"`
+	PostalCode   string `gorm:"type:text"`
+	Entrance     string `gorm:"type:text"`
+	Floor        string `gorm:"type:text"`
+	Apartment    string `gorm:"type:text"`
+	Instructions string `gorm:"type:text"`
+	Contactless  bool
 }
 
 type RestaurantOrderDTO struct { //nolint:govet
@@ -38,37 +79,149 @@ type Meal struct {
 	RestaurantID uuid.UUID `gorm:"type:uuid"`
 }
 
+// ItemDTO represents order line item. Position keeps items in the order customer placed them.
+type ItemDTO struct { //nolint:govet
+	OrderID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Position  int       `gorm:"primaryKey"`
+	MealID    uuid.UUID `gorm:"type:uuid"`
+	Quantity  int
+	UnitPrice int64
+	Notes     string `gorm:"type:text"`
+}
+
+func (ItemDTO) TableName() string { return "order_items" }
+
+// StateChangeDTO represents a single order state transition.
+type StateChangeDTO struct { //nolint:govet
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrderID     uuid.UUID `gorm:"type:uuid;index:idx_order_state_changes_order_at,priority:1"`
+	From        State     `gorm:"type:text"`
+	To          State     `gorm:"type:text"`
+	CausationID string    `gorm:"type:text"`
+	Actor       Actor     `gorm:"type:text"`
+	At          time.Time `gorm:"index:idx_order_state_changes_order_at,priority:2"`
+	ETA         *time.Time
+}
+
+func (StateChangeDTO) TableName() string { return "order_state_changes" }
+
+// CourierAssignmentDTO represents a single courier assignment to order.
+type CourierAssignmentDTO struct { //nolint:govet
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrderID    uuid.UUID `gorm:"type:uuid;index"`
+	CourierID  uuid.UUID `gorm:"type:uuid"`
+	AssignedAt time.Time
+	ReleasedAt *time.Time
+	Reason     string `gorm:"type:text"`
+}
+
+func (CourierAssignmentDTO) TableName() string { return "order_courier_assignments" }
+
 func (d *DatabaseOrderDTO) ToOrder() *Order {
 	dst, _ := destination.NewDestination(d.Latitude, d.Longitude)
+	address, _ := destination.NewAddress(destination.AddressParams(d.Address))
+	dst = dst.WithAddress(address)
+	restaurantLocation, _ := destination.NewDestination(d.RestaurantLat, d.RestaurantLng)
+
+	var cancellation Cancellation
+	if d.CanceledAt != nil {
+		cancellation = Cancellation{
+			reason: d.CancelReason,
+			by:     d.CanceledBy,
+			at:     *d.CanceledAt,
+		}
+	}
+
+	fulfillment := d.Fulfillment
+	if fulfillment == "" {
+		fulfillment = FulfillmentDelivery
+	}
+
+	var deliverAt, releaseAt time.Time
+	if d.DeliverAt != nil {
+		deliverAt = *d.DeliverAt
+	}
+
+	if d.ReleaseAt != nil {
+		releaseAt = *d.ReleaseAt
+	}
+
+	history := make([]StateChange, len(d.History))
 
-	meals := make(uuid.UUIDs, len(d.Restaurant.Meals))
+	for i, change := range d.History {
+		history[i] = stateChangeFromDTO(change)
+	}
+
+	assignments := make([]CourierAssignment, len(d.Assignments))
 
-	for i, meal := range d.Restaurant.Meals {
-		meals[i] = meal.ID
+	for i, assignment := range d.Assignments {
+		assignments[i] = assignmentFromDTO(assignment)
 	}
 
 	return &Order{
 		id:            d.ID,
-		restaurantID:  d.Restaurant.ID,
+		restaurantID:  d.RestaurantID,
 		customerID:    d.CustomerID,
 		courierID:     d.CourierID,
-		meals:         meals,
+		assignments:   assignments,
+		items:         itemsFromDTO(d.Items),
 		state:         d.State,
+		history:       history,
+		cancellation:  cancellation,
 		transactionID: d.TransactionID,
-		destination:   dst,
-		createdAt:     d.CreatedAt,
+		refundID:      d.RefundID,
+
+		refundFailure: d.RefundFailure,
+		pricing: Pricing{
+			currency:    money.Currency(d.Currency),
+			subtotal:    d.Subtotal,
+			deliveryFee: d.DeliveryFee,
+			serviceFee:  d.ServiceFee,
+			tax:         d.Tax,
+			tip:         d.Tip,
+			discount:    d.Discount,
+		},
+		paidAmount:  money.New(d.PaidAmount, money.Currency(d.PaidCurrency)),
+		fulfillment: fulfillment,
+		destination: dst,
+		eta:         fromNullTime(d.ETA),
+		deliverAt:   deliverAt,
+
+		restaurantLocation: restaurantLocation,
+		releaseAt:          releaseAt,
+		createdAt:          d.CreatedAt,
+		version:            d.Version,
 	}
 }
 
 func (o *Order) ToDatabaseDTO() *DatabaseOrderDTO {
-	meals := make([]Meal, len(o.meals))
+	var (
+		meals = make([]Meal, 0, len(o.items))
+		seen  = make(map[uuid.UUID]struct{}, len(o.items))
+	)
+
+	for _, item := range o.items {
+		if _, ok := seen[item.mealID]; !ok {
+			seen[item.mealID] = struct{}{}
+			meals = append(meals, Meal{ID: item.mealID, RestaurantID: o.restaurantID})
+		}
+	}
+
+	history := make([]StateChangeDTO, len(o.history))
 
-	for i, meal := range o.meals {
-		meals[i].ID = meal
+	for i, change := range o.history {
+		history[i] = stateChangeToDTO(o.id, change)
+	}
+
+	assignments := make([]CourierAssignmentDTO, len(o.assignments))
+
+	for i, assignment := range o.assignments {
+		assignments[i] = assignmentToDTO(o.id, assignment)
 	}
 
 	return &DatabaseOrderDTO{
-		ID: o.id,
+		ID:           o.id,
+		RestaurantID: o.restaurantID,
 		Restaurant: RestaurantOrderDTO{
 			ID:    o.restaurantID,
 			Meals: meals,
@@ -77,8 +230,143 @@ func (o *Order) ToDatabaseDTO() *DatabaseOrderDTO {
 		CourierID:     o.courierID,
 		State:         o.state,
 		TransactionID: o.transactionID,
+		CancelReason:  o.cancellation.reason,
+		CanceledBy:    o.cancellation.by,
+		CanceledAt:    toNullTime(o.cancellation.at),
+		RefundID:      o.refundID,
+		RefundFailure: o.refundFailure,
+		Currency:      o.pricing.currency.String(),
+		Subtotal:      o.pricing.subtotal,
+		DeliveryFee:   o.pricing.deliveryFee,
+		ServiceFee:    o.pricing.serviceFee,
+		Tax:           o.pricing.tax,
+		Tip:           o.pricing.tip,
+		Discount:      o.pricing.discount,
+		Total:         o.pricing.total(),
+		PaidAmount:    o.paidAmount.Amount(),
+		PaidCurrency:  o.paidAmount.Currency().String(),
+		Fulfillment:   o.fulfillment,
 		Latitude:      o.destination.Latitude(),
 		Longitude:     o.destination.Longitude(),
+		Address:       addressToDTO(o.destination.Address()),
+		RestaurantLat: o.restaurantLocation.Latitude(),
+		RestaurantLng: o.restaurantLocation.Longitude(),
+		ETA:           toNullTime(o.eta),
+		Items:         itemsToDTO(o.id, o.items),
+		History:       history,
+		Assignments:   assignments,
+		DeliverAt:     toNullTime(o.deliverAt),
+		ReleaseAt:     toNullTime(o.releaseAt),
 		CreatedAt:     o.createdAt,
+		Version:       o.version,
+	}
+}
+
+func itemsToDTO(orderID uuid.UUID, items []Item) []ItemDTO {
+	dto := make([]ItemDTO, len(items))
+
+	for i, item := range items {
+		dto[i] = ItemDTO{
+			OrderID:   orderID,
+			Position:  i,
+			MealID:    item.mealID,
+			Quantity:  item.quantity,
+			UnitPrice: item.unitPrice,
+			Notes:     item.notes,
+		}
+	}
+
+	return dto
+}
+
+func itemsFromDTO(dto []ItemDTO) []Item {
+	items := make([]Item, len(dto))
+
+	for i, item := range dto {
+		items[i] = Item{
+			mealID:    item.MealID,
+			quantity:  item.Quantity,
+			unitPrice: item.UnitPrice,
+			notes:     item.Notes,
+		}
+	}
+
+	return items
+}
+
+func stateChangeToDTO(orderID uuid.UUID, c StateChange) StateChangeDTO {
+	return StateChangeDTO{
+		ID:          c.id,
+		OrderID:     orderID,
+		From:        c.from,
+		To:          c.to,
+		CausationID: c.causationID,
+		Actor:       c.actor,
+		At:          c.at,
+		ETA:         toNullTime(c.eta),
+	}
+}
+
+func stateChangeFromDTO(d StateChangeDTO) StateChange {
+	return StateChange{
+		id:          d.ID,
+		from:        d.From,
+		to:          d.To,
+		causationID: d.CausationID,
+		actor:       d.Actor,
+		at:          d.At,
+		eta:         fromNullTime(d.ETA),
+	}
+}
+
+func assignmentToDTO(orderID uuid.UUID, a CourierAssignment) CourierAssignmentDTO {
+	return CourierAssignmentDTO{
+		ID:         a.id,
+		OrderID:    orderID,
+		CourierID:  a.courierID,
+		AssignedAt: a.assignedAt,
+		ReleasedAt: toNullTime(a.releasedAt),
+		Reason:     a.reason,
+	}
+}
+
+func assignmentFromDTO(d CourierAssignmentDTO) CourierAssignment {
+	return CourierAssignment{
+		id:         d.ID,
+		courierID:  d.CourierID,
+		assignedAt: d.AssignedAt,
+		releasedAt: fromNullTime(d.ReleasedAt),
+		reason:     d.Reason,
+	}
+}
+
+func addressToDTO(a destination.Address) AddressDTO {
+	return AddressDTO{
+		Line1:        a.Line1(),
+		Line2:        a.Line2(),
+		City:         a.City(),
+		PostalCode:   a.PostalCode(),
+		Entrance:     a.Entrance(),
+		Floor:        a.Floor(),
+		Apartment:    a.Apartment(),
+		Instructions: a.Instructions(),
+		Contactless:  a.Contactless(),
 	}
 }
+
+// toNullTime converts zero time to NULL.
+func toNullTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+
+	return &t
+}
+
+func fromNullTime(t *time.Time) time.Time {
+	if t == nil {
+		return time.Time{}
+	}
+
+	return *t
+}
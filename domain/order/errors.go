@@ -5,7 +5,15 @@ import (
 )
 
 var (
+	ErrOrderNotFound = errors.New("order not found")
 	ErrInvalidState  = errors.New("invalid state")
 	ErrOrderClosed   = errors.New("order closed")
 	ErrOrderCanceled = errors.New("order canceled")
+	ErrOrderNotPaid  = errors.New("order not paid")
+	ErrRefundPending = errors.New("refund has not been settled")
+
+	ErrOrderNotModifiable = errors.New("order cannot be modified: items can be modified only while order is created or paid")
+
+	// ErrConcurrentModification is returned when Order has been modified by someone else since it was read.
+	ErrConcurrentModification = errors.New("order has been concurrently modified")
 )
@@ -0,0 +1,110 @@
+package order
+
+import (
+	"github.com/pkg/errors"
+	"service/domain/shared/destination"
+	"time"
+)
+
+const (
+	// DefaultRoadFactor is a ratio of road distance to straight line distance in a city.
+	DefaultRoadFactor = 1.3
+
+	// DefaultPrepTime is expected time restaurant needs to prepare Order.
+	DefaultPrepTime = 20 * time.Minute
+
+	// DefaultCourierSpeed is expected courier speed in kilometers per hour.
+	DefaultCourierSpeed = 20.0
+)
+
+// Estimator estimates when Order is going to reach the customer.
+// Zero time means the estimation is not available.
+type Estimator interface {
+	Estimate(o *Order, at time.Time) time.Time
+}
+
+// ETAEstimator estimates Order delivery time from its State, expected preparation time
+// and time courier needs to get from restaurant to Order destination.
+type ETAEstimator struct {
+	// roadFactor is multiplied by haversine distance to approximate road distance.
+	roadFactor float64
+
+	// prepTime states for expected time restaurant needs to prepare Order.
+	prepTime time.Duration
+
+	// courierSpeed states for expected courier speed in kilometers per hour.
+	courierSpeed float64
+}
+
+// NewETAEstimator creates ETAEstimator.
+// Use road factor 1 to estimate by straight line distance.
+func NewETAEstimator(roadFactor float64, prepTime time.Duration, courierSpeed float64) *ETAEstimator {
+	if roadFactor < 1 || prepTime < 0 || courierSpeed <= 0 {
+		panic(errors.Errorf("eta estimator: invalid parameters: road factor: %f, prep time: %s, courier speed: %f",
+			roadFactor, prepTime, courierSpeed))
+	}
+
+	return &ETAEstimator{
+		roadFactor:   roadFactor,
+		prepTime:     prepTime,
+		courierSpeed: courierSpeed,
+	}
+}
+
+// DefaultETAEstimator estimates ETA with default road factor, preparation time and courier speed.
+var DefaultETAEstimator = NewETAEstimator(DefaultRoadFactor, DefaultPrepTime, DefaultCourierSpeed)
+
+// Estimate returns when Order, that is in its current State at provided time, is going to reach the customer.
+// Scheduled Order is estimated to arrive at requested delivery time.
+// Delivered, closed and canceled orders have no ETA.
+func (e *ETAEstimator) Estimate(o *Order, at time.Time) time.Time {
+	// requested delivery time does not depend on travel time
+	if o.state == Scheduled {
+		return o.deliverAt
+	}
+
+	travel, ok := e.travelTime(o)
+	if !ok {
+		return time.Time{}
+	}
+
+	switch o.state {
+	case Created, Paid, Cooking:
+		return at.Add(e.prepTime + travel)
+	case Finished, WaitingForCourier, CourierTook, Delivering, ReadyForPickup:
+		return at.Add(travel)
+	default:
+		return time.Time{}
+	}
+}
+
+// travelTime returns time courier needs to deliver Order from restaurant to its destination.
+// Pickup Order needs no travel. If restaurant location is unknown, travel time cannot be estimated.
+func (e *ETAEstimator) travelTime(o *Order) (time.Duration, bool) {
+	if o.fulfillment == FulfillmentPickup {
+		return 0, true
+	}
+
+	if o.restaurantLocation.IsZero() {
+		return 0, false
+	}
+
+	distance := o.restaurantLocation.DistanceTo(o.destination) * e.roadFactor
+
+	return time.Duration(distance / e.courierSpeed * float64(time.Hour)), true
+}
+
+// WithRestaurantLocation sets location of the restaurant Order is prepared in.
+// It is used to estimate when Order is going to be delivered.
+func WithRestaurantLocation(latitude, longitude float64) Option {
+	return func(o *Order) error {
+		location, err := destination.NewDestination(latitude, longitude)
+		if err != nil {
+			return errors.WithMessage(err, "cannot resolve restaurant location")
+		}
+
+		o.restaurantLocation = location
+
+		return nil
+	}
+}
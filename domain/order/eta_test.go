@@ -0,0 +1,114 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestETAEstimator_Estimate(t *testing.T) {
+	// restaurant is ~10 km away from destination by straight line
+	o, err := NewOrder(
+		uuid.NewString(),
+		uuid.NewString(),
+		[]ItemParams{{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000}},
+		testCharges,
+		50.0, 30.0,
+		WithRestaurantLocation(50.0, 30.14),
+	)
+	require.NoError(t, err)
+
+	var (
+		at        = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		estimator = NewETAEstimator(1, 15*time.Minute, 20)
+		travel    = time.Duration(o.restaurantLocation.DistanceTo(o.destination) / 20 * float64(time.Hour))
+	)
+
+	require.InDelta(t, 30*time.Minute, travel, float64(time.Minute))
+
+	testCases := []struct { //nolint:govet
+		name     string
+		state    State
+		expected time.Time
+	}{
+		{"created", Created, at.Add(15*time.Minute + travel)},
+		{"cooking", Cooking, at.Add(15*time.Minute + travel)},
+		{"waiting for courier", WaitingForCourier, at.Add(travel)},
+		{"delivering", Delivering, at.Add(travel)},
+		{"delivered", Delivered, time.Time{}},
+		{"canceled", Canceled, time.Time{}},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			o.state = tc.state
+
+			assert.Equal(t, tc.expected, estimator.Estimate(o, at))
+		})
+	}
+
+	t.Run("assert road factor prolongs travel", func(t *testing.T) {
+		o.state = Delivering
+
+		eta := NewETAEstimator(1.5, 0, 20).Estimate(o, at)
+
+		assert.InDelta(t, float64(travel)*1.5, float64(eta.Sub(at)), float64(time.Second))
+	})
+
+	t.Run("assert pickup order needs no travel", func(t *testing.T) {
+		pickupOrder := *o
+		pickupOrder.fulfillment = FulfillmentPickup
+		pickupOrder.state = Cooking
+
+		assert.Equal(t, at.Add(15*time.Minute), estimator.Estimate(&pickupOrder, at))
+	})
+
+	t.Run("assert unknown restaurant location has no estimation", func(t *testing.T) {
+		unknown := *o
+		unknown.restaurantLocation = createOperator(t).o.restaurantLocation
+		unknown.state = Cooking
+
+		assert.True(t, estimator.Estimate(&unknown, at).IsZero())
+	})
+
+	t.Run("assert scheduled order arrives at requested time even if restaurant location is unknown", func(t *testing.T) {
+		scheduled := *o
+		scheduled.restaurantLocation = createOperator(t).o.restaurantLocation
+		scheduled.state = Scheduled
+		scheduled.deliverAt = at.Add(24 * time.Hour)
+
+		assert.Equal(t, scheduled.deliverAt, estimator.Estimate(&scheduled, at))
+	})
+}
+
+func TestStateOperator_setState_ETA(t *testing.T) {
+	t.Run("assert ETA is re-estimated at every transition", func(t *testing.T) {
+		o, err := NewOrder(
+			uuid.NewString(),
+			uuid.NewString(),
+			[]ItemParams{{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000}},
+			testCharges,
+			50.0, 30.0,
+			WithRestaurantLocation(50.0, 30.14),
+		)
+		require.NoError(t, err)
+		require.False(t, o.ETA().IsZero())
+
+		operator := NewStateOperator(o)
+		operator.o.state = Finished
+
+		_, err = operator.WaitForCourier()
+		require.NoError(t, err)
+
+		history := operator.o.History()
+		assert.Equal(t, operator.o.ETA(), history[len(history)-1].ETA())
+		assert.True(t, operator.o.ETA().Before(history[0].ETA()))
+
+		_, err = operator.CancelOrder("")
+		require.NoError(t, err)
+		assert.True(t, operator.o.ETA().IsZero())
+	})
+}
@@ -2,6 +2,8 @@ package event
 
 import (
 	"service/domain/shared/destination"
+	"service/domain/shared/money"
+	"time"
 )
 
 // Type provides methods for converting Order for different marshaling strategies.
@@ -11,7 +13,56 @@ type Type struct {
 	RestaurantID  string
 	TransactionID string
 	Meals         []string
+	Items         []Item
+	Pricing       Pricing
+	Balance       money.Money
+	Fulfillment   string
 	Destination   destination.Destination
+	DeliverAt     time.Time
+	ETA           time.Time
+}
+
+// Pricing represents order monetary breakdown for the events.
+type Pricing struct {
+	Subtotal    money.Money
+	DeliveryFee money.Money
+	ServiceFee  money.Money
+	Tax         money.Money
+	Tip         money.Money
+	Discount    money.Money
+	Total       money.Money
+}
+
+// JSONPricing converts Pricing to JSONPricing.
+func (p Pricing) JSONPricing() JSONPricing {
+	return JSONPricing{
+		Currency:    p.Total.Currency().String(),
+		Subtotal:    p.Subtotal.Amount(),
+		DeliveryFee: p.DeliveryFee.Amount(),
+		ServiceFee:  p.ServiceFee.Amount(),
+		Tax:         p.Tax.Amount(),
+		Tip:         p.Tip.Amount(),
+		Discount:    p.Discount.Amount(),
+		Total:       p.Total.Amount(),
+	}
+}
+
+// Item represents order line item for the events.
+type Item struct {
+	MealID    string
+	Notes     string
+	UnitPrice int64
+	Quantity  int
+}
+
+// JSONItem converts Item to JSONItem.
+func (i Item) JSONItem() JSONItem {
+	return JSONItem{
+		MealID:    i.MealID,
+		Quantity:  i.Quantity,
+		UnitPrice: i.UnitPrice,
+		Notes:     i.Notes,
+	}
 }
 
 // JSONEventOrderCreated converts Type to JSONEventOrderCreated.
@@ -21,6 +72,42 @@ func (t *Type) JSONEventOrderCreated() JSONEventOrderCreated {
 		CustomerID:   t.CustomerID,
 		RestaurantID: t.RestaurantID,
 		Meals:        t.Meals,
+		Items:        jsonItems(t.Items),
+		Pricing:      t.Pricing.JSONPricing(),
+		Fulfillment:  t.Fulfillment,
 		Destination:  t.Destination.ToJSON(),
+		DeliverAt:    jsonTime(t.DeliverAt),
+		ETA:          jsonTime(t.ETA),
+	}
+}
+
+// JSONEventOrderModified converts Type to JSONEventOrderModified.
+func (t *Type) JSONEventOrderModified() JSONEventOrderModified {
+	return JSONEventOrderModified{
+		OrderID:      t.OrderID,
+		RestaurantID: t.RestaurantID,
+		Items:        jsonItems(t.Items),
+		Pricing:      t.Pricing.JSONPricing(),
+		Balance:      t.Balance.Amount(),
+		ETA:          jsonTime(t.ETA),
+	}
+}
+
+func jsonItems(items []Item) []JSONItem {
+	result := make([]JSONItem, len(items))
+
+	for i, item := range items {
+		result[i] = item.JSONItem()
+	}
+
+	return result
+}
+
+// jsonTime returns nil for zero time, so it is omitted from JSON.
+func jsonTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
 	}
+
+	return &t
 }
@@ -3,7 +3,9 @@ package event
 import (
 	"github.com/google/uuid"
 	"service/domain/shared/destination"
+	"service/domain/shared/money"
 	"service/event"
+	"time"
 )
 
 // JSONEventOrderCreated provides JSON representation of Order.
@@ -13,7 +15,70 @@ type JSONEventOrderCreated struct {
 	CustomerID   string                      `json:"customer_id"`
 	RestaurantID string                      `json:"restaurant_id"`
 	Meals        []string                    `json:"meals"`
+	Items        []JSONItem                  `json:"items"`
+	Pricing      JSONPricing                 `json:"pricing"`
+	Fulfillment  string                      `json:"fulfillment"`
 	Destination  destination.JSONDestination `json:"destination"`
+	DeliverAt    *time.Time                  `json:"deliver_at,omitempty"`
+	ETA          *time.Time                  `json:"eta,omitempty"`
+}
+
+// JSONEventOrderModified provides JSON representation of Order`s items after they were modified.
+type JSONEventOrderModified struct {
+	event.Event  `json:"-"`
+	OrderID      string      `json:"order_id"`
+	RestaurantID string      `json:"restaurant_id"`
+	Items        []JSONItem  `json:"items"`
+	Pricing      JSONPricing `json:"pricing"`
+
+	// Balance is the amount to charge in addition to the paid one, negative amount is to be refunded.
+	Balance int64      `json:"balance"`
+	ETA     *time.Time `json:"eta,omitempty"`
+}
+
+// JSONStatusChanged provides JSON representation of a single Order state transition.
+// ID is unique for every transition. From is empty for the very first transition.
+type JSONStatusChanged struct { //nolint:govet
+	event.Event `json:"-"`
+	ID          uuid.UUID  `json:"id"`
+	OrderID     uuid.UUID  `json:"order_id"`
+	From        string     `json:"from,omitempty"`
+	To          string     `json:"to"`
+	Actor       string     `json:"actor"`
+	CausationID string     `json:"causation_id,omitempty"`
+	At          time.Time  `json:"at"`
+	ETA         *time.Time `json:"eta,omitempty"`
+}
+
+// JSONSLABreached provides JSON representation of Order which has stayed in its state longer than allowed.
+type JSONSLABreached struct { //nolint:govet
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+	State       string    `json:"state"`
+	EnteredAt   time.Time `json:"entered_at"`
+	MaxDwell    string    `json:"max_dwell"`
+	Dwell       string    `json:"dwell"`
+	DetectedAt  time.Time `json:"detected_at"`
+}
+
+// JSONItem provides JSON representation of order line item.
+type JSONItem struct {
+	MealID    string `json:"meal_id"`
+	Notes     string `json:"notes,omitempty"`
+	UnitPrice int64  `json:"unit_price"`
+	Quantity  int    `json:"quantity"`
+}
+
+// JSONPricing provides JSON representation of order monetary breakdown in minor units of Currency.
+type JSONPricing struct {
+	Currency    string `json:"currency"`
+	Subtotal    int64  `json:"subtotal"`
+	DeliveryFee int64  `json:"delivery_fee"`
+	ServiceFee  int64  `json:"service_fee"`
+	Tax         int64  `json:"tax"`
+	Tip         int64  `json:"tip"`
+	Discount    int64  `json:"discount"`
+	Total       int64  `json:"total"`
 }
 
 type JSONOrderFinished struct {
@@ -30,6 +95,9 @@ type JSONEventOrderPaid struct {
 	event.Event   `json:"-"`
 	OrderID       uuid.UUID `json:"order_id"`
 	TransactionID uuid.UUID `json:"transaction_id"`
+
+	// Amount is the amount that was actually charged. It is absent in events of older producers.
+	Amount *money.JSONMoney `json:"amount,omitempty"`
 }
 
 type JSONWaitingForCourier struct {
@@ -43,6 +111,20 @@ type JSONCourierTook struct {
 	CourierID   uuid.UUID `json:"courier_id"`
 }
 
+type JSONCourierReleased struct { //nolint:govet
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+	CourierID   uuid.UUID `json:"courier_id"`
+	Reason      string    `json:"reason"`
+}
+
+type JSONCourierReassigned struct { //nolint:govet
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+	CourierID   uuid.UUID `json:"courier_id"`
+	Reason      string    `json:"reason"`
+}
+
 type JSONDelivering struct {
 	event.Event `json:"-"`
 	OrderID     uuid.UUID `json:"order_id"`
@@ -57,9 +139,33 @@ type JSONCanceled struct { //nolint:govet
 	event.Event `json:"-"`
 	OrderID     uuid.UUID `json:"order_id"`
 	Reason      string    `json:"reason"`
+	CanceledBy  string    `json:"canceled_by"`
+	CanceledAt  time.Time `json:"canceled_at"`
 }
 
 type JSONClosed struct {
 	event.Event `json:"-"`
 	OrderID     uuid.UUID `json:"order_id"`
 }
+
+type JSONRefunded struct {
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+	RefundID    uuid.UUID `json:"refund_id"`
+}
+
+type JSONRefundFailed struct { //nolint:govet
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+	Reason      string    `json:"reason"`
+}
+
+type JSONReadyForPickup struct {
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+}
+
+type JSONPickedUp struct {
+	event.Event `json:"-"`
+	OrderID     uuid.UUID `json:"order_id"`
+}
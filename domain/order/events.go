@@ -0,0 +1,270 @@
+package order
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"service/domain/order/event"
+	"service/domain/shared/money"
+	"time"
+)
+
+var ErrUnknownDomainEvent = errors.New("unknown domain event")
+
+// DomainEvent is a fact that has happened to an Order.
+// Every change of an Order is recorded as a DomainEvent, see [Order.Changes],
+// so Order can be rebuilt by applying its events in order they were recorded, see [Replay].
+type DomainEvent interface {
+	// EventName returns a name DomainEvent is stored by. It must not change once events are stored.
+	EventName() string
+
+	apply(o *Order)
+}
+
+// Names of domain events.
+const (
+	EventOrderPlaced       = "order_placed"
+	EventStateChanged      = "state_changed"
+	EventPaymentRecorded   = "payment_recorded"
+	EventItemsModified     = "items_modified"
+	EventOrderCanceled     = "order_canceled"
+	EventRefundCompleted   = "refund_completed"
+	EventRefundFailed      = "refund_failed"
+	EventCourierAssigned   = "courier_assigned"
+	EventCourierUnassigned = "courier_unassigned"
+)
+
+// domainEvents creates an empty DomainEvent by its name.
+var domainEvents = map[string]func() DomainEvent{
+	EventOrderPlaced:       func() DomainEvent { return &OrderPlaced{} },
+	EventStateChanged:      func() DomainEvent { return &StateChanged{} },
+	EventPaymentRecorded:   func() DomainEvent { return &PaymentRecorded{} },
+	EventItemsModified:     func() DomainEvent { return &ItemsModified{} },
+	EventOrderCanceled:     func() DomainEvent { return &OrderCanceled{} },
+	EventRefundCompleted:   func() DomainEvent { return &RefundCompleted{} },
+	EventRefundFailed:      func() DomainEvent { return &RefundAttemptFailed{} },
+	EventCourierAssigned:   func() DomainEvent { return &CourierAssigned{} },
+	EventCourierUnassigned: func() DomainEvent { return &CourierUnassigned{} },
+}
+
+// UnmarshalDomainEvent decodes JSON DomainEvent stored by name.
+func UnmarshalDomainEvent(name string, data []byte) (DomainEvent, error) {
+	newEvent, ok := domainEvents[name]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownDomainEvent, "name: %s", name)
+	}
+
+	e := newEvent()
+	if err := json.Unmarshal(data, e); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal domain event %s", name)
+	}
+
+	return e, nil
+}
+
+// OrderPlaced is recorded when Order is created. It contains the whole Order.
+type OrderPlaced struct {
+	Order *DatabaseOrderDTO `json:"order"`
+}
+
+// StateChanged is recorded at every Order state transition.
+type StateChanged struct {
+	Change StateChangeDTO `json:"change"`
+}
+
+// PaymentRecorded is recorded when Order is paid.
+type PaymentRecorded struct {
+	TransactionID uuid.UUID `json:"transaction_id"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+}
+
+// ItemsModified is recorded when customer replaces Order`s items.
+type ItemsModified struct {
+	Items    []ItemDTO `json:"items"`
+	Subtotal int64     `json:"subtotal"`
+}
+
+// OrderCanceled is recorded when Order is canceled.
+type OrderCanceled struct {
+	Reason string    `json:"reason"`
+	By     Actor     `json:"by"`
+	At     time.Time `json:"at"`
+}
+
+// RefundCompleted is recorded when money of canceled Order is refunded.
+type RefundCompleted struct {
+	RefundID uuid.UUID `json:"refund_id"`
+}
+
+// RefundAttemptFailed is recorded when refund of canceled Order fails.
+type RefundAttemptFailed struct {
+	Reason string `json:"reason"`
+}
+
+// CourierAssigned is recorded when courier is assigned to Order.
+type CourierAssigned struct {
+	Assignment CourierAssignmentDTO `json:"assignment"`
+}
+
+// CourierUnassigned is recorded when the assigned courier is released from Order.
+type CourierUnassigned struct {
+	Reason string    `json:"reason"`
+	At     time.Time `json:"at"`
+}
+
+func (OrderPlaced) EventName() string         { return EventOrderPlaced }
+func (StateChanged) EventName() string        { return EventStateChanged }
+func (PaymentRecorded) EventName() string     { return EventPaymentRecorded }
+func (ItemsModified) EventName() string       { return EventItemsModified }
+func (OrderCanceled) EventName() string       { return EventOrderCanceled }
+func (RefundCompleted) EventName() string     { return EventRefundCompleted }
+func (RefundAttemptFailed) EventName() string { return EventRefundFailed }
+func (CourierAssigned) EventName() string     { return EventCourierAssigned }
+func (CourierUnassigned) EventName() string   { return EventCourierUnassigned }
+
+func (e OrderPlaced) apply(o *Order) {
+	placed := e.Order.ToOrder()
+	placed.version, placed.changes = o.version, o.changes
+
+	*o = *placed
+}
+
+func (e StateChanged) apply(o *Order) {
+	change := stateChangeFromDTO(e.Change)
+
+	o.state = change.to
+	o.eta = change.eta
+	o.history = append(o.history, change)
+}
+
+func (e PaymentRecorded) apply(o *Order) {
+	o.transactionID = e.TransactionID
+	o.paidAmount = money.New(e.Amount, money.Currency(e.Currency))
+}
+
+func (e ItemsModified) apply(o *Order) {
+	o.items = itemsFromDTO(e.Items)
+	o.pricing.subtotal = e.Subtotal
+}
+
+func (e OrderCanceled) apply(o *Order) {
+	o.cancellation = Cancellation{
+		reason: e.Reason,
+		by:     e.By,
+		at:     e.At,
+	}
+}
+
+func (e RefundCompleted) apply(o *Order) {
+	o.refundID = e.RefundID
+	o.refundFailure = ""
+}
+
+func (e RefundAttemptFailed) apply(o *Order) {
+	o.refundFailure = e.Reason
+}
+
+func (e CourierAssigned) apply(o *Order) {
+	assignment := assignmentFromDTO(e.Assignment)
+
+	o.courierID = assignment.courierID
+	o.assignments = append(o.assignments, assignment)
+}
+
+func (e CourierUnassigned) apply(o *Order) {
+	for i := range o.assignments {
+		if o.assignments[i].Active() {
+			o.assignments[i].releasedAt = e.At
+			o.assignments[i].reason = e.Reason
+		}
+	}
+
+	o.courierID = uuid.Nil
+}
+
+// record applies DomainEvent to Order and keeps it in Order`s changes.
+func (o *Order) record(e DomainEvent) {
+	e.apply(o)
+	o.changes = append(o.changes, e)
+}
+
+// Changes returns events recorded since Order has been created or rebuilt, see [Order.CommitChanges].
+func (o *Order) Changes() []DomainEvent {
+	changes := make([]DomainEvent, len(o.changes))
+	copy(changes, o.changes)
+
+	return changes
+}
+
+// CommitChanges marks recorded events as stored: Order`s version is increased by their number
+// and the events are no longer returned by [Order.Changes].
+func (o *Order) CommitChanges() {
+	o.version += int64(len(o.changes))
+	o.changes = nil
+}
+
+// Replay rebuilds Order by applying events on top of snapshot.
+// If snapshot is nil, Order is rebuilt from its very first event which must be [OrderPlaced].
+// Order`s version is increased by every applied event.
+func Replay(snapshot *Order, events []DomainEvent) (*Order, error) {
+	o := &Order{}
+
+	switch {
+	case snapshot != nil:
+		*o = *snapshot
+		o.changes = nil
+	case len(events) == 0:
+		return nil, errors.Wrap(ErrOrderNotFound, "replay: no events")
+	default:
+		if events[0].EventName() != EventOrderPlaced {
+			return nil, errors.Errorf("replay: first event must be %s, got %s", EventOrderPlaced, events[0].EventName())
+		}
+	}
+
+	for _, e := range events {
+		e.apply(o)
+		o.version++
+	}
+
+	return o, nil
+}
+
+// StatusChangedEvent returns an integration event for DomainEvent that changes Order`s state.
+// The very first state of Order is reported by [OrderPlaced]. Other events are not reported.
+func StatusChangedEvent(e DomainEvent) (*event.JSONStatusChanged, bool) {
+	var change StateChangeDTO
+
+	switch e := e.(type) {
+	case StateChanged:
+		change = e.Change
+	case *StateChanged:
+		change = e.Change
+	case OrderPlaced:
+		change = e.initialChange()
+	case *OrderPlaced:
+		change = e.initialChange()
+	default:
+		return nil, false
+	}
+
+	return &event.JSONStatusChanged{
+		ID:          change.ID,
+		OrderID:     change.OrderID,
+		From:        change.From.Name,
+		To:          change.To.Name,
+		Actor:       change.Actor.String(),
+		CausationID: change.CausationID,
+		At:          change.At,
+		ETA:         change.ETA,
+	}, true
+}
+
+// initialChange returns the transition Order has been placed with.
+func (e OrderPlaced) initialChange() StateChangeDTO {
+	if len(e.Order.History) == 0 {
+		return StateChangeDTO{OrderID: e.Order.ID, To: e.Order.State, At: e.Order.CreatedAt}
+	}
+
+	return e.Order.History[0]
+}
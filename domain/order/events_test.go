@@ -0,0 +1,141 @@
+package order
+
+import (
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/money"
+	"testing"
+)
+
+// operatedOrder returns order that has gone through most kinds of changes.
+func operatedOrder(t *testing.T) *Order {
+	t.Helper()
+
+	operator := createOperator(t)
+	o := operator.o
+
+	_, err := operator.ModifyItems([]ItemParams{{MealID: uuid.NewString(), Quantity: 3, UnitPrice: 700}})
+	require.NoError(t, err)
+
+	_, err = operator.PayOrder(uuid.New(), money.New(2100, "USD"))
+	require.NoError(t, err)
+
+	for _, step := range []func() (bool, error){operator.CookOrder, operator.OrderFinished, operator.WaitForCourier} {
+		_, err = step()
+		require.NoError(t, err)
+	}
+
+	courierID := uuid.New()
+
+	_, err = operator.CourierTookOrder(courierID)
+	require.NoError(t, err)
+
+	_, err = operator.ReassignCourier(uuid.New(), "courier is late")
+	require.NoError(t, err)
+
+	_, err = operator.CancelOrder("customer changed mind")
+	require.NoError(t, err)
+
+	_, err = operator.FailRefund("payment provider is down")
+	require.NoError(t, err)
+
+	return o
+}
+
+func TestReplay(t *testing.T) {
+	t.Run("assert order is rebuilt from its changes", func(t *testing.T) {
+		o := operatedOrder(t)
+
+		replayed, err := Replay(nil, o.Changes())
+		require.NoError(t, err)
+
+		o.CommitChanges()
+
+		assert.Equal(t, o, replayed)
+		assert.Equal(t, int64(16), replayed.Version())
+	})
+
+	t.Run("assert order is rebuilt from stored events", func(t *testing.T) {
+		o := operatedOrder(t)
+
+		events := make([]DomainEvent, 0, len(o.Changes()))
+
+		for _, change := range o.Changes() {
+			data, err := json.Marshal(change)
+			require.NoError(t, err)
+
+			e, err := UnmarshalDomainEvent(change.EventName(), data)
+			require.NoError(t, err)
+
+			events = append(events, e)
+		}
+
+		replayed, err := Replay(nil, events)
+		require.NoError(t, err)
+
+		assert.Equal(t, o.State(), replayed.State())
+		assert.Equal(t, o.Items(), replayed.Items())
+		assert.Equal(t, o.Pricing(), replayed.Pricing())
+		assert.Equal(t, o.PaidAmount(), replayed.PaidAmount())
+		assert.Equal(t, o.CourierID(), replayed.CourierID())
+		assert.Len(t, replayed.Assignments(), 2)
+		assert.Len(t, replayed.History(), len(o.History()))
+		assert.Equal(t, o.Cancellation().Reason(), replayed.Cancellation().Reason())
+		assert.Equal(t, o.RefundFailureReason(), replayed.RefundFailureReason())
+	})
+
+	t.Run("assert order is rebuilt on top of snapshot", func(t *testing.T) {
+		o := createOperator(t).o
+		o.CommitChanges()
+
+		operator := NewStateOperator(o)
+		_, err := operator.PayOrder(uuid.New(), o.Total())
+		require.NoError(t, err)
+
+		replayed, err := Replay(o.ToDatabaseDTO().ToOrder(), o.Changes())
+		require.NoError(t, err)
+
+		assert.Equal(t, Paid, replayed.State())
+		assert.Equal(t, int64(1), o.Version())
+		assert.Equal(t, int64(3), replayed.Version())
+	})
+
+	t.Run("assert order cannot be rebuilt without placement", func(t *testing.T) {
+		_, err := Replay(nil, nil)
+		assert.ErrorIs(t, err, ErrOrderNotFound)
+
+		_, err = Replay(nil, []DomainEvent{RefundCompleted{RefundID: uuid.New()}})
+		assert.Error(t, err)
+	})
+
+	t.Run("assert unknown event is not decoded", func(t *testing.T) {
+		_, err := UnmarshalDomainEvent("order_teleported", []byte(`{}`))
+		assert.ErrorIs(t, err, ErrUnknownDomainEvent)
+	})
+}
+
+func TestStatusChangedEvent(t *testing.T) {
+	operator := createOperator(t)
+
+	_, err := operator.PayOrder(uuid.New(), operator.o.Total())
+	require.NoError(t, err)
+
+	var statuses []string
+
+	for _, change := range operator.o.Changes() {
+		e, ok := StatusChangedEvent(change)
+		if !ok {
+			continue
+		}
+
+		assert.Equal(t, operator.o.ID(), e.OrderID)
+		assert.NotEqual(t, uuid.Nil, e.ID)
+
+		statuses = append(statuses, e.From+"->"+e.To)
+	}
+
+	assert.Equal(t, []string{"->" + Created.Name, Created.Name + "->" + Paid.Name}, statuses,
+		"payment itself is not a status change")
+}
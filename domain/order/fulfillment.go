@@ -0,0 +1,65 @@
+package order
+
+import (
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrInvalidFulfillment  = errors.New("invalid fulfillment: must be delivery or pickup")
+	ErrFulfillmentMismatch = errors.New("transition does not match order fulfillment")
+)
+
+// Fulfillment states for how Order gets to the customer.
+type Fulfillment string
+
+func (f Fulfillment) String() string { return string(f) }
+
+const (
+	// FulfillmentDelivery means Order is delivered to its destination by a courier.
+	FulfillmentDelivery Fulfillment = "delivery"
+
+	// FulfillmentPickup means customer picks Order up in the restaurant. Pickup Order has no destination.
+	FulfillmentPickup Fulfillment = "pickup"
+)
+
+// ParseFulfillment returns Fulfillment by its name.
+func ParseFulfillment(name string) (Fulfillment, error) {
+	switch f := Fulfillment(name); f {
+	case FulfillmentDelivery, FulfillmentPickup:
+		return f, nil
+	default:
+		return "", errors.Wrapf(ErrInvalidFulfillment, "unknown fulfillment: %s", name)
+	}
+}
+
+// WithFulfillment sets how Order gets to the customer. By default, Order is delivered.
+func WithFulfillment(f Fulfillment) Option {
+	return func(o *Order) error {
+		fulfillment, err := ParseFulfillment(f.String())
+		if err != nil {
+			return err
+		}
+
+		o.fulfillment = fulfillment
+
+		return nil
+	}
+}
+
+// delivery checks if Order is delivered by a courier.
+func delivery(o *Order) error {
+	if o.fulfillment != FulfillmentDelivery {
+		return errors.Wrapf(ErrFulfillmentMismatch, "order is fulfilled by %s", o.fulfillment)
+	}
+
+	return nil
+}
+
+// pickup checks if Order is picked up by the customer.
+func pickup(o *Order) error {
+	if o.fulfillment != FulfillmentPickup {
+		return errors.Wrapf(ErrFulfillmentMismatch, "order is fulfilled by %s", o.fulfillment)
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+// StateChange represents a single Order state transition.
+// StateChange is a value object.
+type StateChange struct {
+	// id states for StateChange [uuid].
+	id uuid.UUID
+
+	// from states for a State Order has been in before the transition.
+	// It is empty for the very first StateChange.
+	from State
+
+	// to states for a State Order has been set to.
+	to State
+
+	// causationID states for id of an event or a message that triggered the transition.
+	causationID string
+
+	// actor states for a party that triggered the transition.
+	actor Actor
+
+	// at represents when the transition has happened.
+	at time.Time
+
+	// eta represents when Order has been expected to reach the customer after the transition.
+	eta time.Time
+}
+
+func (c StateChange) ID() uuid.UUID       { return c.id }
+func (c StateChange) From() State         { return c.from }
+func (c StateChange) To() State           { return c.to }
+func (c StateChange) CausationID() string { return c.causationID }
+func (c StateChange) Actor() Actor        { return c.actor }
+func (c StateChange) At() time.Time       { return c.at }
+func (c StateChange) ETA() time.Time      { return c.eta }
+
+func newStateChange(from, to State, actor Actor, causationID string, eta time.Time) StateChange {
+	return StateChange{
+		id:          uuid.New(),
+		from:        from,
+		to:          to,
+		causationID: causationID,
+		actor:       actor,
+		at:          time.Now(),
+		eta:         eta,
+	}
+}
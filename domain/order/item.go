@@ -0,0 +1,119 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxItemQuantity is the maximum quantity of a single meal in one Item.
+	MaxItemQuantity = 99
+
+	// MaxItemNotesLength is the maximum length of Item notes.
+	MaxItemNotesLength = 256
+)
+
+var (
+	ErrNoItems              = errors.New("order must contain at least one item")
+	ErrInvalidItemQuantity  = errors.Errorf("invalid item quantity: must be between 1 and %d", MaxItemQuantity)
+	ErrInvalidItemUnitPrice = errors.New("invalid item unit price: must not be negative")
+	ErrItemNotesTooLong     = errors.Errorf("item notes are too long: must be at most %d characters", MaxItemNotesLength)
+)
+
+// Item represents a single order line: a meal, how many of it customer wants and its price.
+// Item is a value object.
+type Item struct {
+	// mealID states for meal [uuid] in a specific restaurant.
+	mealID uuid.UUID
+
+	// quantity states for how many meals customer ordered.
+	quantity int
+
+	// unitPrice states for price of a single meal in minor units.
+	unitPrice int64
+
+	// notes contains customer`s wishes for the meal.
+	notes string
+}
+
+func (i Item) MealID() uuid.UUID { return i.mealID }
+func (i Item) Quantity() int     { return i.quantity }
+func (i Item) UnitPrice() int64  { return i.unitPrice }
+func (i Item) Notes() string     { return i.notes }
+
+// Total returns price of the Item: unit price multiplied by quantity.
+func (i Item) Total() int64 {
+	return i.unitPrice * int64(i.quantity)
+}
+
+// ItemParams contains raw Item data. Used to create an Item.
+type ItemParams struct {
+	MealID    string
+	Notes     string
+	UnitPrice int64
+	Quantity  int
+}
+
+// NewItem creates new Item.
+func NewItem(p ItemParams) (Item, error) {
+	var errs error
+
+	mealID, err := uuid.Parse(p.MealID)
+	if err != nil {
+		errs = multierror.Append(errs,
+			errors.WithMessage(err, "cannot parse meal id"))
+	}
+
+	if p.Quantity < 1 || p.Quantity > MaxItemQuantity {
+		errs = multierror.Append(errs, ErrInvalidItemQuantity)
+	}
+
+	if p.UnitPrice < 0 {
+		errs = multierror.Append(errs, ErrInvalidItemUnitPrice)
+	}
+
+	if len([]rune(p.Notes)) > MaxItemNotesLength {
+		errs = multierror.Append(errs, ErrItemNotesTooLong)
+	}
+
+	if errs != nil {
+		return Item{}, errs
+	}
+
+	return Item{
+		mealID:    mealID,
+		quantity:  p.Quantity,
+		unitPrice: p.UnitPrice,
+		notes:     p.Notes,
+	}, nil
+}
+
+// newItems converts provided params in slice of Item.
+// If one error occurred while converting - an error returned.
+func newItems(params []ItemParams) ([]Item, error) {
+	if len(params) == 0 {
+		return nil, ErrNoItems
+	}
+
+	var (
+		errs  = make([]error, 0, len(params))
+		items = make([]Item, len(params))
+	)
+
+	for i, p := range params {
+		item, err := NewItem(p)
+		switch err {
+		case nil:
+			items[i] = item
+		default:
+			errs = append(errs, errors.Wrapf(err, "invalid item on index: %d", i))
+		}
+	}
+
+	if len(errs) != 0 {
+		return nil, multierror.Append(nil, errs...)
+	}
+
+	return items, nil
+}
@@ -0,0 +1,88 @@
+package order
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"service/domain/shared/destination"
+	"time"
+)
+
+// MaxLocationClockSkew is how far ahead of now time courier location is taken at may be.
+// Location from the future would hide every location tracked after it, see [LocationTracker].
+const MaxLocationClockSkew = 5 * time.Second
+
+var (
+	ErrCourierNotTracked = errors.New("courier is not tracked: order is not being delivered by courier")
+	ErrLocationNotFound  = errors.New("courier location not found")
+	ErrLocationInFuture  = errors.New("courier location is taken in the future")
+)
+
+// trackedStates lists states courier delivering Order is tracked in.
+var trackedStates = []State{CourierTook, Delivering}
+
+// CourierLocation is a position of the courier delivering Order.
+type CourierLocation struct { //nolint:govet
+	OrderID   uuid.UUID
+	CourierID uuid.UUID
+	Position  destination.Destination
+	At        time.Time
+}
+
+// CourierTracked tells if location of the courier delivering Order is tracked.
+func (o *Order) CourierTracked() bool {
+	for _, state := range trackedStates {
+		if o.Is(state) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// TrackCourier returns location of the courier delivering Order. Location is taken now if at is zero.
+// Location taken more than [MaxLocationClockSkew] ahead of now is rejected with [ErrLocationInFuture].
+func (o *Order) TrackCourier(courierID uuid.UUID, lat, long float64, at time.Time) (CourierLocation, error) {
+	if !o.CourierTracked() {
+		return CourierLocation{}, errors.Wrapf(ErrCourierNotTracked, "order is %s", o.state)
+	}
+
+	if courierID != o.courierID {
+		return CourierLocation{}, errors.Wrapf(ErrCourierNotAssigned, "courier %s", courierID)
+	}
+
+	position, err := destination.NewDestination(lat, long)
+	if err != nil {
+		return CourierLocation{}, err
+	}
+
+	now := time.Now()
+
+	switch {
+	case at.IsZero():
+		at = now
+	case at.After(now.Add(MaxLocationClockSkew)):
+		return CourierLocation{}, errors.Wrapf(ErrLocationInFuture, "location is taken at %s", at)
+	}
+
+	return CourierLocation{
+		OrderID:   o.id,
+		CourierID: courierID,
+		Position:  position,
+		At:        at,
+	}, nil
+}
+
+// LocationTracker keeps the latest location of couriers delivering orders and relays locations to subscribers.
+type LocationTracker interface {
+	// Track stores l as the latest courier location of the order and sends it to subscribers of the order.
+	// Location tracked before the latest one is ignored.
+	Track(ctx context.Context, l CourierLocation) error
+
+	// Latest returns the latest courier location of the order. [ErrLocationNotFound] is returned if there is none.
+	Latest(ctx context.Context, orderID uuid.UUID) (CourierLocation, error)
+
+	// Subscribe returns channel which receives courier locations of the order tracked from now on.
+	// Subscriber which is behind gets only the latest location. Channel is closed when ctx is done.
+	Subscribe(ctx context.Context, orderID uuid.UUID) (<-chan CourierLocation, error)
+}
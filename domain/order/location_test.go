@@ -0,0 +1,90 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"testing"
+	"time"
+)
+
+func TestOrder_TrackCourier(t *testing.T) {
+	take := func(t *testing.T) (*StateOperator, uuid.UUID) {
+		operator := createOperator(t)
+		operator.o.state = WaitingForCourier
+		courierID := uuid.New()
+
+		_, err := operator.CourierTookOrder(courierID)
+		require.NoError(t, err)
+
+		return operator, courierID
+	}
+
+	t.Run("assert courier is tracked while delivering order", func(t *testing.T) {
+		operator, courierID := take(t)
+		at := time.Now().Add(-time.Second)
+
+		l, err := operator.o.TrackCourier(courierID, 50.45, 30.52, at)
+		require.NoError(t, err)
+		assert.Equal(t, operator.o.ID(), l.OrderID)
+		assert.Equal(t, courierID, l.CourierID)
+		assert.Equal(t, 50.45, l.Position.Latitude())
+		assert.Equal(t, 30.52, l.Position.Longitude())
+		assert.Equal(t, at, l.At)
+
+		_, err = operator.DeliveringOrder()
+		require.NoError(t, err)
+
+		l, err = operator.o.TrackCourier(courierID, 50.46, 30.53, time.Time{})
+		require.NoError(t, err)
+		assert.False(t, l.At.IsZero())
+	})
+
+	t.Run("assert courier is not tracked after order is delivered", func(t *testing.T) {
+		operator, courierID := take(t)
+
+		_, err := operator.DeliveringOrder()
+		require.NoError(t, err)
+
+		_, err = operator.OrderDelivered()
+		require.NoError(t, err)
+
+		assert.False(t, operator.o.CourierTracked())
+
+		_, err = operator.o.TrackCourier(courierID, 50.45, 30.52, time.Time{})
+		assert.ErrorIs(t, err, ErrCourierNotTracked)
+	})
+
+	t.Run("assert order waiting for courier is not tracked", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.state = WaitingForCourier
+
+		_, err := operator.o.TrackCourier(uuid.New(), 50.45, 30.52, time.Time{})
+		assert.ErrorIs(t, err, ErrCourierNotTracked)
+	})
+
+	t.Run("assert another courier is not tracked", func(t *testing.T) {
+		operator, _ := take(t)
+
+		_, err := operator.o.TrackCourier(uuid.New(), 50.45, 30.52, time.Time{})
+		assert.ErrorIs(t, err, ErrCourierNotAssigned)
+	})
+
+	t.Run("assert location from the future is rejected", func(t *testing.T) {
+		operator, courierID := take(t)
+
+		_, err := operator.o.TrackCourier(courierID, 50.45, 30.52, time.Now().Add(time.Hour))
+		assert.ErrorIs(t, err, ErrLocationInFuture)
+
+		_, err = operator.o.TrackCourier(courierID, 50.45, 30.52, time.Now().Add(MaxLocationClockSkew/2))
+		assert.NoError(t, err, "location within clock skew must be tracked")
+	})
+
+	t.Run("assert invalid position is rejected", func(t *testing.T) {
+		operator, courierID := take(t)
+
+		_, err := operator.o.TrackCourier(courierID, 91, 30.52, time.Time{})
+		assert.ErrorIs(t, err, destination.ErrInvalidLatitude)
+	})
+}
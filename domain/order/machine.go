@@ -0,0 +1,243 @@
+package order
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Command represents an intention to change Order`s state.
+type Command string
+
+func (c Command) String() string { return string(c) }
+
+const (
+	CommandRelease          Command = "release"
+	CommandPay              Command = "pay"
+	CommandCook             Command = "cook"
+	CommandFinishCooking    Command = "finish_cooking"
+	CommandWaitForCourier   Command = "wait_for_courier"
+	CommandTakeByCourier    Command = "take_by_courier"
+	CommandReleaseCourier   Command = "release_courier"
+	CommandReassignCourier  Command = "reassign_courier"
+	CommandStartDelivery    Command = "start_delivery"
+	CommandCompleteDelivery Command = "complete_delivery"
+	CommandReadyForPickup   Command = "ready_for_pickup"
+	CommandPickUp           Command = "pick_up"
+	CommandCancel           Command = "cancel"
+	CommandClose            Command = "close"
+	CommandRequestRefund    Command = "request_refund"
+	CommandCompleteRefund   Command = "complete_refund"
+	CommandFailRefund       Command = "fail_refund"
+)
+
+// Guard checks if Order can go through a Transition.
+// Guard must not modify the Order.
+type Guard func(o *Order) error
+
+// Transition describes how Order moves From one State To another by Command.
+// Transition is applied only if every Guard passes.
+type Transition struct {
+	From    State
+	To      State
+	Command Command
+	Guards  []Guard
+}
+
+// InvalidTransitionError is returned when Command cannot be applied to Order in From State.
+// It unwraps to [ErrOrderClosed] for closed orders, to [ErrOrderCanceled] for canceled
+// and refunding orders and to [ErrInvalidState] otherwise.
+type InvalidTransitionError struct {
+	From    State
+	Command Command
+	Allowed []State
+}
+
+func (e *InvalidTransitionError) Error() string {
+	allowed := make([]string, len(e.Allowed))
+	for i, state := range e.Allowed {
+		allowed[i] = state.Name
+	}
+
+	return fmt.Sprintf("cannot %s order in state %s: allowed next states: [%s]",
+		e.Command, e.From, strings.Join(allowed, ", "))
+}
+
+func (e *InvalidTransitionError) Unwrap() error {
+	switch e.From {
+	case Closed:
+		return ErrOrderClosed
+	case Canceled, RefundRequested, Refunded, RefundFailed:
+		return ErrOrderCanceled
+	default:
+		return ErrInvalidState
+	}
+}
+
+// StateMachine is a table of transitions keyed by State and Command.
+type StateMachine struct {
+	// transitions holds transitions from each State in order they were registered.
+	transitions map[State][]Transition
+
+	// targets holds every State each Command leads to.
+	targets map[Command]map[State]struct{}
+}
+
+// NewStateMachine creates StateMachine from transitions.
+// It panics if the same Command is registered twice for one State.
+func NewStateMachine(transitions ...Transition) *StateMachine {
+	m := &StateMachine{
+		transitions: make(map[State][]Transition),
+		targets:     make(map[Command]map[State]struct{}),
+	}
+
+	for _, t := range transitions {
+		if _, ok := m.lookup(t.From, t.Command); ok {
+			panic(fmt.Sprintf("state machine: duplicate transition %s from %s", t.Command, t.From))
+		}
+
+		m.transitions[t.From] = append(m.transitions[t.From], t)
+
+		if m.targets[t.Command] == nil {
+			m.targets[t.Command] = make(map[State]struct{})
+		}
+
+		m.targets[t.Command][t.To] = struct{}{}
+	}
+
+	return m
+}
+
+// Fire resolves State Order moves to by Command.
+// If Order is already in a State the Command leads to, Fire returns the current State and false
+// as if the Command has been already applied.
+// If Command cannot be applied to Order`s State, [*InvalidTransitionError] is returned.
+func (m *StateMachine) Fire(o *Order, cmd Command) (next State, changed bool, err error) {
+	t, ok := m.lookup(o.state, cmd)
+	if !ok {
+		if _, done := m.targets[cmd][o.state]; done {
+			return o.state, false, nil
+		}
+
+		return State{}, false, &InvalidTransitionError{
+			From:    o.state,
+			Command: cmd,
+			Allowed: m.Allowed(o.state),
+		}
+	}
+
+	for _, guard := range t.Guards {
+		if err = guard(o); err != nil {
+			return State{}, false, errors.Wrapf(err, "cannot %s order in state %s", cmd, o.state)
+		}
+	}
+
+	return t.To, t.To != o.state, nil
+}
+
+// Can shows if Command can be applied to Order in its current State.
+func (m *StateMachine) Can(o *Order, cmd Command) bool {
+	_, _, err := m.Fire(o, cmd)
+	return err == nil
+}
+
+// Allowed returns States Order can move to from provided State.
+func (m *StateMachine) Allowed(from State) []State {
+	allowed := make([]State, 0, len(m.transitions[from]))
+
+	for _, t := range m.transitions[from] {
+		allowed = append(allowed, t.To)
+	}
+
+	return allowed
+}
+
+func (m *StateMachine) lookup(from State, cmd Command) (Transition, bool) {
+	for _, t := range m.transitions[from] {
+		if t.Command == cmd {
+			return t, true
+		}
+	}
+
+	return Transition{}, false
+}
+
+// DefaultStateMachine describes the delivery flow of an order:
+// [Scheduled ->] Created -> Paid -> Cooking -> Finished -> WaitingForCourier -> CourierTook -> Delivering -> Delivered -> Closed.
+//
+// Courier can drop the order or be replaced before delivery starts:
+// CourierTook -> WaitingForCourier, CourierTook -> CourierTook.
+//
+// Pickup order skips courier states:
+// Finished -> ReadyForPickup -> PickedUp -> Closed.
+//
+// Every State can go into Canceled and Closed State.
+// Canceled order goes into Closed only if it has not been paid. Otherwise, it waits for the refund:
+// Canceled -> RefundRequested -> Refunded -> Closed.
+// RefundRequested -> RefundFailed -> RefundRequested.
+var DefaultStateMachine = NewStateMachine(defaultTransitions()...)
+
+func defaultTransitions() []Transition {
+	transitions := []Transition{
+		{From: Scheduled, Command: CommandRelease, To: Created},
+		{From: Created, Command: CommandPay, To: Paid},
+		{From: Paid, Command: CommandCook, To: Cooking},
+		{From: Cooking, Command: CommandFinishCooking, To: Finished},
+		{From: Finished, Command: CommandWaitForCourier, To: WaitingForCourier, Guards: []Guard{delivery}},
+		{From: WaitingForCourier, Command: CommandTakeByCourier, To: CourierTook},
+		{From: CourierTook, Command: CommandReleaseCourier, To: WaitingForCourier},
+		{From: CourierTook, Command: CommandReassignCourier, To: CourierTook},
+		{From: CourierTook, Command: CommandStartDelivery, To: Delivering},
+		{From: Delivering, Command: CommandCompleteDelivery, To: Delivered},
+		{From: Finished, Command: CommandReadyForPickup, To: ReadyForPickup, Guards: []Guard{pickup}},
+		{From: ReadyForPickup, Command: CommandPickUp, To: PickedUp},
+		{From: Canceled, Command: CommandClose, To: Closed, Guards: []Guard{refundSettled}},
+		{From: Canceled, Command: CommandRequestRefund, To: RefundRequested, Guards: []Guard{paid}},
+		{From: RefundFailed, Command: CommandRequestRefund, To: RefundRequested},
+		{From: RefundRequested, Command: CommandCompleteRefund, To: Refunded},
+		{From: RefundRequested, Command: CommandFailRefund, To: RefundFailed},
+		{From: Refunded, Command: CommandClose, To: Closed},
+	}
+
+	active := []State{
+		Scheduled,
+		Created,
+		Paid,
+		Cooking,
+		Finished,
+		WaitingForCourier,
+		CourierTook,
+		Delivering,
+		Delivered,
+		ReadyForPickup,
+		PickedUp,
+	}
+
+	for _, state := range active {
+		transitions = append(transitions,
+			Transition{From: state, Command: CommandCancel, To: Canceled},
+			Transition{From: state, Command: CommandClose, To: Closed},
+		)
+	}
+
+	return transitions
+}
+
+// paid checks if Order has been paid.
+func paid(o *Order) error {
+	if o.transactionID == uuid.Nil {
+		return ErrOrderNotPaid
+	}
+
+	return nil
+}
+
+// refundSettled checks if paid Order has been refunded.
+func refundSettled(o *Order) error {
+	if o.transactionID != uuid.Nil {
+		return ErrRefundPending
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package order
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStateMachine_Fire(t *testing.T) {
+	t.Run("assert invalid transition names allowed states", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.state = Finished
+
+		_, err := operator.CookOrder()
+
+		var transitionErr *InvalidTransitionError
+
+		require.ErrorAs(t, err, &transitionErr)
+		assert.ErrorIs(t, err, ErrInvalidState)
+		assert.Equal(t, Finished, transitionErr.From)
+		assert.Equal(t, CommandCook, transitionErr.Command)
+		assert.Equal(t, []State{WaitingForCourier, ReadyForPickup, Canceled, Closed}, transitionErr.Allowed)
+	})
+
+	t.Run("assert canceled order can be closed", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.state = Canceled
+
+		closed, err := operator.CloseOrder()
+
+		assert.NoError(t, err)
+		assert.True(t, closed)
+		assert.Equal(t, Closed, operator.o.State())
+	})
+
+	t.Run("assert custom machine branches without changing operator", func(t *testing.T) {
+		errNotAllowed := errors.New("not allowed")
+		allowed := true
+
+		machine := NewStateMachine(
+			Transition{From: Created, Command: CommandPay, To: Paid},
+			Transition{
+				From:    Paid,
+				Command: CommandFinishCooking,
+				To:      Finished,
+				Guards: []Guard{func(*Order) error {
+					if !allowed {
+						return errNotAllowed
+					}
+
+					return nil
+				}},
+			},
+		)
+
+		operator := NewStateOperator(createOperator(t).o, WithStateMachine(machine))
+		operator.o.state = Paid
+
+		allowed = false
+
+		_, err := operator.OrderFinished()
+		assert.ErrorIs(t, err, errNotAllowed)
+		assert.Equal(t, Paid, operator.o.State())
+
+		allowed = true
+
+		finished, err := operator.OrderFinished()
+		assert.NoError(t, err)
+		assert.True(t, finished)
+		assert.Equal(t, Finished, operator.o.State())
+	})
+}
+
+func TestNewStateMachine(t *testing.T) {
+	t.Run("assert duplicate transition panics", func(t *testing.T) {
+		assert.Panics(t, func() {
+			NewStateMachine(
+				Transition{From: Created, Command: CommandPay, To: Paid},
+				Transition{From: Created, Command: CommandPay, To: Cooking},
+			)
+		})
+	})
+}
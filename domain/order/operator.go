@@ -3,40 +3,276 @@ package order
 import (
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"service/domain/shared/money"
+	"time"
 )
 
 // StateOperator provides methods to operate with order state.
 // USe StateOperator to operate on order state.
+// Every state transition is recorded into the Order`s history.
 type StateOperator struct {
 	o *Order
+
+	// machine describes which transitions are allowed.
+	machine *StateMachine
+
+	// actor states for a party on whose behalf state is changed.
+	actor Actor
+
+	// causationID states for id of an event or a message that caused state changes.
+	causationID string
+
+	// policy decides who may cancel the order.
+	policy CancellationPolicy
+
+	// estimator re-estimates order`s ETA at every state transition.
+	estimator Estimator
+}
+
+// OperatorOption configures StateOperator.
+type OperatorOption func(*StateOperator)
+
+// WithActor sets a party on whose behalf StateOperator changes state.
+func WithActor(actor Actor) OperatorOption {
+	return func(s *StateOperator) {
+		s.actor = actor
+	}
+}
+
+// WithCausationID sets id of an event or a message that caused state changes.
+func WithCausationID(id string) OperatorOption {
+	return func(s *StateOperator) {
+		s.causationID = id
+	}
+}
+
+// WithStateMachine sets StateMachine that StateOperator uses to resolve transitions.
+func WithStateMachine(m *StateMachine) OperatorOption {
+	return func(s *StateOperator) {
+		s.machine = m
+	}
+}
+
+// WithCancellationPolicy sets CancellationPolicy that decides who may cancel the order.
+func WithCancellationPolicy(p CancellationPolicy) OperatorOption {
+	return func(s *StateOperator) {
+		s.policy = p
+	}
+}
+
+// WithEstimator sets Estimator that re-estimates order`s ETA at every state transition.
+func WithEstimator(e Estimator) OperatorOption {
+	return func(s *StateOperator) {
+		s.estimator = e
+	}
 }
 
 // NewStateOperator creates a new StateOperator.
-func NewStateOperator(o *Order) *StateOperator {
-	return &StateOperator{o: o}
+// By default, it uses [DefaultStateMachine], [DefaultCancellationPolicy], [DefaultETAEstimator]
+// and records state changes on behalf of [ActorSystem].
+func NewStateOperator(o *Order, opts ...OperatorOption) *StateOperator {
+	s := &StateOperator{
+		o:       o,
+		machine: DefaultStateMachine,
+		actor:   ActorSystem,
+		policy:  DefaultCancellationPolicy,
+
+		estimator: DefaultETAEstimator,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
 }
 
-// CancelOrder set orders`s state to [Canceled].
+// CancelOrder set orders`s state to [Canceled] on behalf of the operator`s actor
+// and records the cancellation reason, party and time.
+// If order has been paid, the refund is requested immediately: order`s state is set to [RefundRequested].
+// If order has been already canceled, it returns true and a nil error.
 // If order is closed, it returns an error.
-func (s *StateOperator) CancelOrder(_ string) (bool, error) {
-	return s.trySetState(Canceled)
+// If actor is not allowed to cancel order in its state, it returns [ErrCancellationForbidden].
+func (s *StateOperator) CancelOrder(reason string) (bool, error) {
+	if len([]rune(reason)) > MaxCancellationReasonLength {
+		return false, ErrCancellationReasonTooLong
+	}
+
+	if !s.o.cancellation.IsZero() && !s.o.Is(Closed) {
+		return true, nil
+	}
+
+	next, changed, err := s.machine.Fire(s.o, CommandCancel)
+	if err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return true, nil
+	}
+
+	if err = s.policy.CanCancel(s.o, s.actor); err != nil {
+		return false, err
+	}
+
+	s.setState(next)
+
+	s.o.record(OrderCanceled{
+		Reason: reason,
+		By:     s.actor,
+		At:     time.Now(),
+	})
+
+	if s.o.transactionID != uuid.Nil {
+		return s.RequestRefund()
+	}
+
+	return true, nil
+}
+
+// refundLatePayment records payment of the order canceled before it has been paid and requests its refund.
+// If payment has been already recorded, it is not recorded again and it returns true and a nil error.
+// If order is closed, it returns an error.
+func (s *StateOperator) refundLatePayment(transactionID uuid.UUID, charged money.Money) (bool, error) {
+	if s.o.transactionID != uuid.Nil {
+		return true, nil
+	}
+
+	if !s.o.Is(Canceled) {
+		_, _, err := s.machine.Fire(s.o, CommandPay)
+		return false, err
+	}
+
+	if err := s.checkCharged(charged); err != nil {
+		return false, err
+	}
+
+	s.o.record(PaymentRecorded{
+		TransactionID: transactionID,
+		Amount:        charged.Amount(),
+		Currency:      charged.Currency().String(),
+	})
+
+	return s.RequestRefund()
+}
+
+// RequestRefund set orders`s state to [RefundRequested].
+// Refund can be requested only for canceled paid order or after the previous refund failed.
+func (s *StateOperator) RequestRefund() (bool, error) {
+	return s.fire(CommandRequestRefund)
+}
+
+// RefundOrder set orders`s state to [Refunded] and records refund [uuid].
+// If order has been already refunded, refund is not recorded again and it returns true and a nil error.
+// If order is closed, it returns an error.
+func (s *StateOperator) RefundOrder(refundID uuid.UUID) (bool, error) {
+	next, changed, err := s.machine.Fire(s.o, CommandCompleteRefund)
+	if err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return true, nil
+	}
+
+	s.setState(next)
+
+	s.o.record(RefundCompleted{RefundID: refundID})
+
+	return true, nil
+}
+
+// FailRefund set orders`s state to [RefundFailed] and records why refund failed.
+// If order is closed, it returns an error.
+func (s *StateOperator) FailRefund(reason string) (bool, error) {
+	failed, err := s.fire(CommandFailRefund)
+	if err != nil || !failed {
+		return false, err
+	}
+
+	s.o.record(RefundAttemptFailed{Reason: reason})
+
+	return true, nil
 }
 
 // CloseOrder set orders`s state to [Closed].
 // If order is closed, it returns a nil error.
 func (s *StateOperator) CloseOrder() (bool, error) {
-	return s.trySetState(Closed)
+	return s.fire(CommandClose)
 }
 
-// PayOrder set orders`s state to [Paid].
-// If order is closed, it returns an error.
-func (s *StateOperator) PayOrder(transactionID uuid.UUID) (bool, error) {
-	set, err := s.trySetState(Paid)
-	if err != nil || !set {
+// ReleaseOrder set scheduled orders`s state to [Created], so it goes through the normal flow.
+// If order has been already released, it returns a nil error.
+func (s *StateOperator) ReleaseOrder() (bool, error) {
+	return s.fire(CommandRelease)
+}
+
+// PayOrder set orders`s state to [Paid] and records the amount that was actually charged.
+// If order has been already paid, payment is not recorded again and it returns true and a nil error.
+// Payment which arrives after order has been canceled, e.g. on [ReasonPaymentTimeout], is recorded and refunded.
+// If order is closed or charged amount is not in order`s currency, it returns an error.
+func (s *StateOperator) PayOrder(transactionID uuid.UUID, charged money.Money) (bool, error) {
+	if !s.o.cancellation.IsZero() {
+		return s.refundLatePayment(transactionID, charged)
+	}
+
+	next, changed, err := s.machine.Fire(s.o, CommandPay)
+	if err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return true, nil
+	}
+
+	if err = s.checkCharged(charged); err != nil {
+		return false, err
+	}
+
+	s.setState(next)
+
+	s.o.record(PaymentRecorded{
+		TransactionID: transactionID,
+		Amount:        charged.Amount(),
+		Currency:      charged.Currency().String(),
+	})
+
+	return true, nil
+}
+
+// checkCharged checks if order has been charged in its currency, otherwise [money.ErrCurrencyMismatch] is returned.
+func (s *StateOperator) checkCharged(charged money.Money) error {
+	if charged.Currency() != s.o.pricing.Currency() {
+		return errors.Wrapf(money.ErrCurrencyMismatch, "charged in %s, order is in %s", charged.Currency(), s.o.pricing.Currency())
+	}
+
+	return nil
+}
+
+// ModifyItems replaces order`s items with provided ones and recalculates its pricing.
+// Items can be modified only while order is [Created] or [Paid],
+// otherwise [ErrOrderNotModifiable] is returned.
+// Total of paid order may differ from the charged amount afterward, see [Order.Balance].
+// Order`s state is not changed.
+func (s *StateOperator) ModifyItems(params []ItemParams) (bool, error) {
+	if !s.o.Is(Created) && !s.o.Is(Paid) {
+		return false, errors.Wrapf(ErrOrderNotModifiable, "order is in state %s", s.o.state)
+	}
+
+	items, err := newItems(params)
+	if err != nil {
 		return false, err
 	}
 
-	s.o.transactionID = transactionID
+	pricing, err := s.o.pricing.withItems(items)
+	if err != nil {
+		return false, err
+	}
+
+	s.o.record(ItemsModified{
+		Items:    itemsToDTO(s.o.id, items),
+		Subtotal: pricing.subtotal,
+	})
 
 	return true, nil
 }
@@ -44,30 +280,89 @@ func (s *StateOperator) PayOrder(transactionID uuid.UUID) (bool, error) {
 // CookOrder set orders`s state to [Cooking].
 // If order is closed, it returns an error.
 func (s *StateOperator) CookOrder() (bool, error) {
-	return s.trySetState(Cooking)
+	return s.fire(CommandCook)
 }
 
 // OrderFinished set orders`s state to [Finished].
 // If order is closed, it returns an error.
 func (s *StateOperator) OrderFinished() (bool, error) {
-	return s.trySetState(Finished)
+	return s.fire(CommandFinishCooking)
 }
 
 // WaitForCourier set orders`s state to [WaitingForCourier].
 // If order is closed, it returns an error.
 func (s *StateOperator) WaitForCourier() (bool, error) {
-	return s.trySetState(WaitingForCourier)
+	return s.fire(CommandWaitForCourier)
 }
 
-// CourierTookOrder set orders`s state to [CourierTook].
+// OrderReadyForPickup set orders`s state to [ReadyForPickup].
+// If order is delivered by a courier, it returns [ErrFulfillmentMismatch].
+func (s *StateOperator) OrderReadyForPickup() (bool, error) {
+	return s.fire(CommandReadyForPickup)
+}
+
+// OrderPickedUp set orders`s state to [PickedUp].
+// If order is closed, it returns an error.
+func (s *StateOperator) OrderPickedUp() (bool, error) {
+	return s.fire(CommandPickUp)
+}
+
+// CourierTookOrder set orders`s state to [CourierTook] and assigns the courier.
 // If order is closed, it returns an error.
 func (s *StateOperator) CourierTookOrder(courierID uuid.UUID) (bool, error) {
-	changed, err := s.trySetState(CourierTook)
-	if err != nil || !changed {
+	next, changed, err := s.machine.Fire(s.o, CommandTakeByCourier)
+	if err != nil {
 		return false, errors.Wrapf(err, "failed to set courier took order state")
 	}
 
-	s.o.courierID = courierID
+	if !changed {
+		return true, nil
+	}
+
+	s.setState(next)
+	s.o.assignCourier(courierID)
+
+	return true, nil
+}
+
+// ReleaseCourier set orders`s state back to [WaitingForCourier] and releases the assigned courier,
+// so another courier can take the order.
+// If courier has been already released, it returns true and a nil error.
+// If provided courier is not assigned to the order, it returns [ErrCourierNotAssigned].
+func (s *StateOperator) ReleaseCourier(courierID uuid.UUID, reason string) (bool, error) {
+	next, changed, err := s.machine.Fire(s.o, CommandReleaseCourier)
+	if err != nil {
+		return false, err
+	}
+
+	if !changed {
+		return true, nil
+	}
+
+	if s.o.courierID != courierID {
+		return false, errors.Wrapf(ErrCourierNotAssigned, "courier: %s", courierID)
+	}
+
+	s.setState(next)
+	s.o.releaseCourier(reason)
+
+	return true, nil
+}
+
+// ReassignCourier replaces the assigned courier with provided one. Order stays in [CourierTook] state.
+// Previous assignment is kept in order`s assignments.
+// If courier is already assigned, it returns true and a nil error.
+func (s *StateOperator) ReassignCourier(courierID uuid.UUID, reason string) (bool, error) {
+	if _, _, err := s.machine.Fire(s.o, CommandReassignCourier); err != nil {
+		return false, err
+	}
+
+	if s.o.courierID == courierID {
+		return true, nil
+	}
+
+	s.o.releaseCourier(reason)
+	s.o.assignCourier(courierID)
 
 	return true, nil
 }
@@ -75,56 +370,41 @@ func (s *StateOperator) CourierTookOrder(courierID uuid.UUID) (bool, error) {
 // DeliveringOrder set orders`s state to [Delivering].
 // If order is closed, it returns an error.
 func (s *StateOperator) DeliveringOrder() (bool, error) {
-	return s.trySetState(Delivering)
+	return s.fire(CommandStartDelivery)
 }
 
 // OrderDelivered set orders`s state to [Delivered].
 // If order is closed, it returns an error.
 func (s *StateOperator) OrderDelivered() (bool, error) {
-	return s.trySetState(Delivered)
+	return s.fire(CommandCompleteDelivery)
 }
 
-// trySetState tries set state to the order.
-// If next State equals to Order`s state. The returned boolean will be true and error is nil.
-// As if verb of the State were done.
-// If next state is [Canceled] or [Closed] it sets it immediately.
-// Otherwise, it checks if the next state is the same as the current order state.
-// If it is, it sets the next state.
-func (s *StateOperator) trySetState(next State) (bool, error) {
-	orderState := s.o.state
-
-	if orderState == next {
-		return true, nil
-	}
-
-	if s.o.Is(Closed) {
-		return false, errors.Wrapf(ErrOrderClosed, "cannot set state: %s", next.Name)
-	}
-
-	if s.o.Is(Canceled) {
-		return false, errors.Wrapf(ErrOrderCanceled, "cannot set state: %s", next.Name)
+// fire applies Command to the order.
+// If order is already in a State the Command leads to, the returned boolean will be true and error is nil.
+// As if the Command were done.
+// Otherwise, it resolves the next state via StateMachine and sets it.
+func (s *StateOperator) fire(cmd Command) (bool, error) {
+	next, changed, err := s.machine.Fire(s.o, cmd)
+	if err != nil {
+		return false, err
 	}
 
-	if next == Canceled || next == Closed {
+	if changed {
 		s.setState(next)
-		return true, nil
 	}
 
-	if orderState.Next.Name != next.Name {
-		return false, errors.Wrapf(ErrInvalidState, "cannot set state %#v", next)
-	}
-
-	s.nextState()
-
 	return true, nil
 }
 
-// nextState sets order`s state to the next
-func (s *StateOperator) nextState() {
-	s.o.state = *s.o.state.Next
-}
-
-// setState sets provided state to the current order.
+// setState sets order`s state, re-estimates its ETA and records the transition into order`s history.
 func (s *StateOperator) setState(state State) {
-	s.o.state = state
+	// ETA is estimated for the order in the next state
+	next := *s.o
+	next.state = state
+
+	eta := s.estimator.Estimate(&next, time.Now())
+
+	s.o.record(StateChanged{
+		Change: stateChangeToDTO(s.o.id, newStateChange(s.o.state, state, s.actor, s.causationID, eta)),
+	})
 }
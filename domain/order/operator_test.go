@@ -3,6 +3,9 @@ package order
 import (
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/money"
+	"strings"
 	"testing"
 )
 
@@ -10,9 +13,11 @@ func createOperator(t *testing.T) *StateOperator {
 	order, err := NewOrder(
 		uuid.NewString(),
 		uuid.NewString(),
-		[]string{
-			uuid.NewString(), uuid.NewString(),
+		[]ItemParams{
+			{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+			{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 550},
 		},
+		testCharges,
 		0.0,
 		0.0,
 	)
@@ -27,9 +32,11 @@ func TestNewStateOperator(t *testing.T) {
 		order, err := NewOrder(
 			uuid.NewString(),
 			uuid.NewString(),
-			[]string{
-				uuid.NewString(), uuid.NewString(),
+			[]ItemParams{
+				{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+				{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 550},
 			},
+			testCharges,
 			0.0,
 			0.0,
 		)
@@ -56,12 +63,12 @@ func TestStateOperator_setState(t *testing.T) {
 	})
 }
 
-func TestStateOperator_trySetState(t *testing.T) {
+func TestStateOperator_fire(t *testing.T) {
 	testCases := []struct { //nolint:govet
 		name string
 
-		operatorState  State
-		replacingState State
+		operatorState State
+		command       Command
 
 		wantErr bool
 
@@ -69,60 +76,60 @@ func TestStateOperator_trySetState(t *testing.T) {
 		expectedErr error
 	}{
 		{
-			name:           "OK",
-			operatorState:  Delivering,
-			replacingState: Delivered,
+			name:          "OK",
+			operatorState: Delivering,
+			command:       CommandCompleteDelivery,
 
 			wantErr: false,
 			setted:  true,
 		},
 		{
-			name:           "set state to the closed order",
-			operatorState:  Closed,
-			replacingState: Delivering,
+			name:          "set state to the closed order",
+			operatorState: Closed,
+			command:       CommandStartDelivery,
 
 			wantErr:     true,
 			setted:      false,
 			expectedErr: ErrOrderClosed,
 		},
 		{
-			name:           "cancel order",
-			operatorState:  Created,
-			replacingState: Canceled,
+			name:          "cancel order",
+			operatorState: Created,
+			command:       CommandCancel,
 
 			wantErr: false,
 			setted:  true,
 		},
 		{
-			name:           "canceling setted order",
-			operatorState:  Canceled,
-			replacingState: Canceled,
+			name:          "canceling setted order",
+			operatorState: Canceled,
+			command:       CommandCancel,
 
 			wantErr: false,
 			setted:  true,
 		},
 		{
-			name:           "canceling closed order",
-			operatorState:  Closed,
-			replacingState: Canceled,
+			name:          "canceling closed order",
+			operatorState: Closed,
+			command:       CommandCancel,
 
 			wantErr:     true,
 			setted:      false,
 			expectedErr: ErrOrderClosed,
 		},
 		{
-			name:           "set past state",
-			operatorState:  Finished,
-			replacingState: Cooking,
+			name:          "set past state",
+			operatorState: Finished,
+			command:       CommandCook,
 
 			wantErr:     true,
 			setted:      false,
 			expectedErr: ErrInvalidState,
 		},
 		{
-			name:           "setting state to the setted order",
-			operatorState:  Canceled,
-			replacingState: Cooking,
+			name:          "setting state to the setted order",
+			operatorState: Canceled,
+			command:       CommandCook,
 
 			wantErr:     true,
 			setted:      false,
@@ -136,7 +143,7 @@ func TestStateOperator_trySetState(t *testing.T) {
 
 			operator.o.state = tc.operatorState
 
-			canceled, err := operator.trySetState(tc.replacingState)
+			canceled, err := operator.fire(tc.command)
 			if tc.wantErr {
 				assert.ErrorIs(t, err, tc.expectedErr)
 			} else {
@@ -264,3 +271,560 @@ func TestStateOperator_FromAnyStateToClosed(t *testing.T) {
 		assert.NoError(t, err)
 	}
 }
+
+func TestStateOperator_PayOrder(t *testing.T) {
+	t.Run("assert PayOrder records transaction and charged amount", func(t *testing.T) {
+		operator := createOperator(t)
+		transactionID := uuid.New()
+		charged := money.New(2100, "USD")
+
+		paid, err := operator.PayOrder(transactionID, charged)
+
+		assert.NoError(t, err)
+		assert.True(t, paid)
+		assert.Equal(t, Paid, operator.o.State())
+		assert.Equal(t, transactionID, operator.o.TransactionID())
+		assert.Equal(t, charged, operator.o.PaidAmount())
+	})
+
+	t.Run("assert redelivered payment is not recorded again", func(t *testing.T) {
+		operator := createOperator(t)
+		transactionID := uuid.New()
+		charged := money.New(2100, "USD")
+
+		_, err := operator.PayOrder(transactionID, charged)
+		require.NoError(t, err)
+
+		operator.o.CommitChanges()
+
+		paid, err := operator.PayOrder(uuid.New(), money.New(1, "USD"))
+		assert.NoError(t, err)
+		assert.True(t, paid)
+		assert.Empty(t, operator.o.Changes())
+		assert.Equal(t, transactionID, operator.o.TransactionID())
+		assert.Equal(t, charged, operator.o.PaidAmount())
+	})
+
+	t.Run("assert payment in another currency is rejected", func(t *testing.T) {
+		operator := createOperator(t)
+
+		paid, err := operator.PayOrder(uuid.New(), money.New(2100, "EUR"))
+		assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+		assert.False(t, paid)
+		assert.Equal(t, Created, operator.o.State())
+		assert.Equal(t, uuid.Nil, operator.o.TransactionID())
+	})
+
+	t.Run("assert payment of order canceled on payment timeout is refunded", func(t *testing.T) {
+		operator := createOperator(t)
+		transactionID := uuid.New()
+		charged := money.New(2100, "USD")
+
+		_, err := NewStateOperator(operator.o, WithActor(ActorSystem)).CancelOrder(ReasonPaymentTimeout)
+		require.NoError(t, err)
+
+		paid, err := operator.PayOrder(transactionID, charged)
+		require.NoError(t, err)
+		assert.True(t, paid)
+		assert.Equal(t, RefundRequested, operator.o.State())
+		assert.Equal(t, transactionID, operator.o.TransactionID())
+		assert.Equal(t, charged, operator.o.PaidAmount())
+
+		operator.o.CommitChanges()
+
+		paid, err = operator.PayOrder(transactionID, charged)
+		assert.NoError(t, err)
+		assert.True(t, paid)
+		assert.Empty(t, operator.o.Changes(), "redelivered payment must not be recorded again")
+	})
+
+	t.Run("assert payment of order canceled by customer is refunded", func(t *testing.T) {
+		operator := createOperator(t)
+		transactionID := uuid.New()
+
+		_, err := operator.CancelOrder("changed mind")
+		require.NoError(t, err)
+
+		paid, err := operator.PayOrder(transactionID, money.New(2100, "USD"))
+		require.NoError(t, err)
+		assert.True(t, paid)
+		assert.Equal(t, RefundRequested, operator.o.State())
+		assert.Equal(t, transactionID, operator.o.TransactionID())
+	})
+
+	t.Run("assert payment of closed order is rejected", func(t *testing.T) {
+		operator := createOperator(t)
+
+		_, err := operator.CancelOrder("changed mind")
+		require.NoError(t, err)
+		_, err = operator.CloseOrder()
+		require.NoError(t, err)
+
+		paid, err := operator.PayOrder(uuid.New(), money.New(2100, "USD"))
+		assert.ErrorIs(t, err, ErrOrderClosed)
+		assert.False(t, paid)
+		assert.Equal(t, uuid.Nil, operator.o.TransactionID())
+	})
+}
+
+func TestStateOperator_History(t *testing.T) {
+	t.Run("assert transitions are recorded with actor and causation id", func(t *testing.T) {
+		order, err := NewOrder(
+			uuid.NewString(),
+			uuid.NewString(),
+			[]ItemParams{{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000}},
+			testCharges,
+			0.0,
+			0.0,
+		)
+		assert.NoError(t, err)
+
+		causationID := uuid.NewString()
+		operator := NewStateOperator(order, WithActor(ActorPayment), WithCausationID(causationID))
+
+		_, err = operator.PayOrder(uuid.New(), money.New(1000, "USD"))
+		assert.NoError(t, err)
+
+		// repeated transition must not be recorded
+		_, err = operator.PayOrder(uuid.New(), money.New(1000, "USD"))
+		assert.NoError(t, err)
+
+		history := order.History()
+
+		assert.Len(t, history, 2)
+
+		assert.Equal(t, State{}, history[0].From())
+		assert.Equal(t, Created, history[0].To())
+		assert.Equal(t, ActorCustomer, history[0].Actor())
+
+		assert.Equal(t, Created, history[1].From())
+		assert.Equal(t, Paid, history[1].To())
+		assert.Equal(t, ActorPayment, history[1].Actor())
+		assert.Equal(t, causationID, history[1].CausationID())
+		assert.False(t, history[1].At().Before(history[0].At()))
+	})
+}
+
+func TestStateOperator_CancelOrder(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name string
+
+		operatorState State
+		actor         Actor
+		reason        string
+
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:          "customer cancels cooking order",
+			operatorState: Cooking,
+			actor:         ActorCustomer,
+			reason:        "changed my mind",
+		},
+		{
+			name:          "customer cannot cancel delivering order",
+			operatorState: Delivering,
+			actor:         ActorCustomer,
+			reason:        "too late",
+			wantErr:       true,
+			expectedErr:   ErrCancellationForbidden,
+		},
+		{
+			name:          "restaurant cannot cancel order taken by courier",
+			operatorState: CourierTook,
+			actor:         ActorRestaurant,
+			wantErr:       true,
+			expectedErr:   ErrCancellationForbidden,
+		},
+		{
+			name:          "system cancels delivering order",
+			operatorState: Delivering,
+			actor:         ActorSystem,
+			reason:        "courier accident",
+		},
+		{
+			name:          "support agent cancels delivered order",
+			operatorState: Delivered,
+			actor:         ActorSupport,
+			reason:        "wrong meals delivered",
+		},
+		{
+			name:          "payment provider cannot cancel order",
+			operatorState: Created,
+			actor:         ActorPayment,
+			wantErr:       true,
+			expectedErr:   ErrCancellationForbidden,
+		},
+		{
+			name:          "too long reason",
+			operatorState: Created,
+			actor:         ActorCustomer,
+			reason:        strings.Repeat("a", MaxCancellationReasonLength+1),
+			wantErr:       true,
+			expectedErr:   ErrCancellationReasonTooLong,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			operator := createOperator(t)
+			operator.o.state = tc.operatorState
+			operator.actor = tc.actor
+
+			canceled, err := operator.CancelOrder(tc.reason)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				assert.False(t, canceled)
+				assert.Equal(t, tc.operatorState, operator.o.State())
+				assert.True(t, operator.o.Cancellation().IsZero())
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.True(t, canceled)
+			assert.Equal(t, Canceled, operator.o.State())
+
+			cancellation := operator.o.Cancellation()
+			assert.Equal(t, tc.reason, cancellation.Reason())
+			assert.Equal(t, tc.actor, cancellation.By())
+			assert.False(t, cancellation.At().IsZero())
+		})
+	}
+}
+
+func TestStateOperator_Refund(t *testing.T) {
+	payAndCancel := func(t *testing.T) *StateOperator {
+		operator := createOperator(t)
+
+		_, err := operator.PayOrder(uuid.New(), money.New(2100, "USD"))
+		require.NoError(t, err)
+
+		canceled, err := operator.CancelOrder("changed my mind")
+		require.NoError(t, err)
+		require.True(t, canceled)
+
+		return operator
+	}
+
+	t.Run("assert canceling paid order requests refund", func(t *testing.T) {
+		operator := payAndCancel(t)
+
+		assert.Equal(t, RefundRequested, operator.o.State())
+		assert.Equal(t, "changed my mind", operator.o.Cancellation().Reason())
+
+		canceled, err := operator.CancelOrder("again")
+		assert.NoError(t, err)
+		assert.True(t, canceled)
+		assert.Equal(t, RefundRequested, operator.o.State())
+		assert.Equal(t, "changed my mind", operator.o.Cancellation().Reason())
+	})
+
+	t.Run("assert paid order is closed only after refund", func(t *testing.T) {
+		operator := payAndCancel(t)
+
+		closed, err := operator.CloseOrder()
+		assert.ErrorIs(t, err, ErrOrderCanceled)
+		assert.False(t, closed)
+
+		refundID := uuid.New()
+
+		refunded, err := operator.RefundOrder(refundID)
+		require.NoError(t, err)
+		assert.True(t, refunded)
+		assert.Equal(t, refundID, operator.o.RefundID())
+
+		closed, err = operator.CloseOrder()
+		assert.NoError(t, err)
+		assert.True(t, closed)
+		assert.Equal(t, Closed, operator.o.State())
+	})
+
+	t.Run("assert redelivered refund is not recorded again", func(t *testing.T) {
+		operator := payAndCancel(t)
+		refundID := uuid.New()
+
+		_, err := operator.RefundOrder(refundID)
+		require.NoError(t, err)
+
+		operator.o.CommitChanges()
+
+		refunded, err := operator.RefundOrder(uuid.New())
+		assert.NoError(t, err)
+		assert.True(t, refunded)
+		assert.Empty(t, operator.o.Changes())
+		assert.Equal(t, refundID, operator.o.RefundID())
+	})
+
+	t.Run("assert failed refund can be requested again", func(t *testing.T) {
+		operator := payAndCancel(t)
+
+		failed, err := operator.FailRefund("card expired")
+		require.NoError(t, err)
+		assert.True(t, failed)
+		assert.Equal(t, RefundFailed, operator.o.State())
+		assert.Equal(t, "card expired", operator.o.RefundFailureReason())
+
+		closed, err := operator.CloseOrder()
+		assert.ErrorIs(t, err, ErrOrderCanceled)
+		assert.False(t, closed)
+
+		requested, err := operator.RequestRefund()
+		assert.NoError(t, err)
+		assert.True(t, requested)
+		assert.Equal(t, RefundRequested, operator.o.State())
+	})
+
+	t.Run("assert canceled paid order cannot skip refund", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.transactionID = uuid.New()
+		operator.o.state = Canceled
+
+		closed, err := operator.CloseOrder()
+		assert.ErrorIs(t, err, ErrRefundPending)
+		assert.False(t, closed)
+	})
+
+	t.Run("assert unpaid order is closed without refund", func(t *testing.T) {
+		operator := createOperator(t)
+
+		canceled, err := operator.CancelOrder("")
+		require.NoError(t, err)
+		assert.True(t, canceled)
+		assert.Equal(t, Canceled, operator.o.State())
+
+		requested, err := operator.RequestRefund()
+		assert.ErrorIs(t, err, ErrOrderNotPaid)
+		assert.False(t, requested)
+
+		closed, err := operator.CloseOrder()
+		assert.NoError(t, err)
+		assert.True(t, closed)
+	})
+}
+
+func TestStateOperator_ModifyItems(t *testing.T) {
+	items := []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 3, UnitPrice: 400},
+	}
+
+	testCases := []struct { //nolint:govet
+		name     string
+		state    State
+		params   []ItemParams
+		modified bool
+		err      error
+	}{
+		{"created order", Created, items, true, nil},
+		{"paid order", Paid, items, true, nil},
+		{"cooking order", Cooking, items, false, ErrOrderNotModifiable},
+		{"delivering order", Delivering, items, false, ErrOrderNotModifiable},
+		{"canceled order", Canceled, items, false, ErrOrderNotModifiable},
+		{"no items", Created, nil, false, ErrNoItems},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			operator := createOperator(t)
+			operator.o.state = tc.state
+			before := operator.o.Items()
+
+			modified, err := operator.ModifyItems(tc.params)
+
+			assert.ErrorIs(t, err, tc.err)
+			assert.Equal(t, tc.modified, modified)
+			assert.Equal(t, tc.state, operator.o.State())
+
+			if !tc.modified {
+				assert.Equal(t, before, operator.o.Items())
+				return
+			}
+
+			assert.Len(t, operator.o.Items(), 1)
+			assert.Equal(t, int64(1200), operator.o.Subtotal().Amount())
+		})
+	}
+
+	t.Run("assert balance of paid order is the difference of modified total and charged amount", func(t *testing.T) {
+		operator := createOperator(t)
+		charged := operator.o.Total()
+
+		assert.True(t, operator.o.Balance().IsZero(), "unpaid order has no balance")
+
+		_, err := operator.PayOrder(uuid.New(), charged)
+		require.NoError(t, err)
+		assert.True(t, operator.o.Balance().IsZero())
+
+		_, err = operator.ModifyItems(items)
+		require.NoError(t, err)
+
+		assert.Equal(t, money.New(operator.o.Total().Amount()-charged.Amount(), "USD"), operator.o.Balance())
+		assert.True(t, operator.o.Balance().IsNegative(), "customer is owed a refund for removed items")
+	})
+
+	t.Run("assert discount must not exceed modified total", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.pricing.discount = 2000
+
+		modified, err := operator.ModifyItems(items)
+
+		assert.ErrorIs(t, err, ErrDiscountExceedsTotal)
+		assert.False(t, modified)
+		assert.Equal(t, int64(2100), operator.o.Subtotal().Amount())
+	})
+}
+
+func TestStateOperator_ReleaseOrder(t *testing.T) {
+	t.Run("assert scheduled order is released into the flow", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.state = Scheduled
+
+		_, err := operator.PayOrder(uuid.New(), money.New(2100, "USD"))
+		assert.ErrorIs(t, err, ErrInvalidState)
+
+		released, err := operator.ReleaseOrder()
+		assert.NoError(t, err)
+		assert.True(t, released)
+		assert.Equal(t, Created, operator.o.State())
+
+		released, err = operator.ReleaseOrder()
+		assert.NoError(t, err)
+		assert.True(t, released)
+		assert.Len(t, operator.o.History(), 2)
+	})
+
+	t.Run("assert scheduled order can be canceled", func(t *testing.T) {
+		operator := NewStateOperator(createOperator(t).o, WithActor(ActorCustomer))
+		operator.o.state = Scheduled
+
+		canceled, err := operator.CancelOrder("plans changed")
+		assert.NoError(t, err)
+		assert.True(t, canceled)
+		assert.Equal(t, Canceled, operator.o.State())
+	})
+}
+
+func TestStateOperator_Pickup(t *testing.T) {
+	t.Run("assert pickup order skips courier states", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.fulfillment = FulfillmentPickup
+		operator.o.state = Finished
+
+		_, err := operator.WaitForCourier()
+		assert.ErrorIs(t, err, ErrFulfillmentMismatch)
+
+		ready, err := operator.OrderReadyForPickup()
+		require.NoError(t, err)
+		assert.True(t, ready)
+		assert.Equal(t, ReadyForPickup, operator.o.State())
+
+		pickedUp, err := operator.OrderPickedUp()
+		require.NoError(t, err)
+		assert.True(t, pickedUp)
+		assert.Equal(t, PickedUp, operator.o.State())
+
+		closed, err := operator.CloseOrder()
+		assert.NoError(t, err)
+		assert.True(t, closed)
+		assert.Equal(t, Closed, operator.o.State())
+	})
+
+	t.Run("assert delivery order cannot be picked up", func(t *testing.T) {
+		operator := createOperator(t)
+		operator.o.state = Finished
+
+		ready, err := operator.OrderReadyForPickup()
+		assert.ErrorIs(t, err, ErrFulfillmentMismatch)
+		assert.False(t, ready)
+		assert.Equal(t, Finished, operator.o.State())
+	})
+}
+
+func TestStateOperator_Courier(t *testing.T) {
+	take := func(t *testing.T) (*StateOperator, uuid.UUID) {
+		operator := createOperator(t)
+		operator.o.state = WaitingForCourier
+		courierID := uuid.New()
+
+		taken, err := operator.CourierTookOrder(courierID)
+		require.NoError(t, err)
+		require.True(t, taken)
+
+		return operator, courierID
+	}
+
+	t.Run("assert released order waits for another courier", func(t *testing.T) {
+		operator, courierID := take(t)
+
+		released, err := operator.ReleaseCourier(courierID, "flat tire")
+		require.NoError(t, err)
+		assert.True(t, released)
+		assert.Equal(t, WaitingForCourier, operator.o.State())
+		assert.Equal(t, uuid.Nil, operator.o.CourierID())
+
+		released, err = operator.ReleaseCourier(courierID, "flat tire")
+		assert.NoError(t, err)
+		assert.True(t, released)
+
+		nextCourierID := uuid.New()
+
+		taken, err := operator.CourierTookOrder(nextCourierID)
+		require.NoError(t, err)
+		assert.True(t, taken)
+		assert.Equal(t, nextCourierID, operator.o.CourierID())
+
+		assignments := operator.o.Assignments()
+		require.Len(t, assignments, 2)
+		assert.Equal(t, courierID, assignments[0].CourierID())
+		assert.False(t, assignments[0].Active())
+		assert.Equal(t, "flat tire", assignments[0].Reason())
+		assert.Equal(t, nextCourierID, assignments[1].CourierID())
+		assert.True(t, assignments[1].Active())
+	})
+
+	t.Run("assert only assigned courier can be released", func(t *testing.T) {
+		operator, courierID := take(t)
+
+		released, err := operator.ReleaseCourier(uuid.New(), "")
+		assert.ErrorIs(t, err, ErrCourierNotAssigned)
+		assert.False(t, released)
+		assert.Equal(t, CourierTook, operator.o.State())
+		assert.Equal(t, courierID, operator.o.CourierID())
+	})
+
+	t.Run("assert reassignment keeps previous assignments", func(t *testing.T) {
+		operator, courierID := take(t)
+		nextCourierID := uuid.New()
+
+		reassigned, err := operator.ReassignCourier(nextCourierID, "closer courier")
+		require.NoError(t, err)
+		assert.True(t, reassigned)
+		assert.Equal(t, CourierTook, operator.o.State())
+		assert.Equal(t, nextCourierID, operator.o.CourierID())
+
+		reassigned, err = operator.ReassignCourier(nextCourierID, "closer courier")
+		assert.NoError(t, err)
+		assert.True(t, reassigned)
+
+		assignments := operator.o.Assignments()
+		require.Len(t, assignments, 2)
+		assert.Equal(t, courierID, assignments[0].CourierID())
+		assert.Equal(t, "closer courier", assignments[0].Reason())
+		assert.Equal(t, nextCourierID, assignments[1].CourierID())
+	})
+
+	t.Run("assert courier cannot be released after delivery started", func(t *testing.T) {
+		operator, courierID := take(t)
+		operator.o.state = Delivering
+
+		released, err := operator.ReleaseCourier(courierID, "")
+		assert.ErrorIs(t, err, ErrInvalidState)
+		assert.False(t, released)
+
+		reassigned, err := operator.ReassignCourier(uuid.New(), "")
+		assert.ErrorIs(t, err, ErrInvalidState)
+		assert.False(t, reassigned)
+	})
+}
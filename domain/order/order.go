@@ -7,6 +7,7 @@ import (
 	"github.com/pkg/errors"
 	"service/domain/order/event"
 	"service/domain/shared/destination"
+	"service/domain/shared/money"
 	"time"
 )
 
@@ -22,35 +23,77 @@ type Order struct { //nolint:govet
 	// customerID states for user [uuid].
 	customerID uuid.UUID
 
-	// courierID states for courier [uuid].
+	// courierID states for currently assigned courier [uuid].
+	// It is [uuid.Nil] if no courier is assigned.
 	courierID uuid.UUID
 
-	// meals states for meals` [uuid] that user selected in a specific restaurant.
-	meals uuid.UUIDs
+	// assignments contains every courier assignment in chronological order.
+	assignments []CourierAssignment
+
+	// items states for meals that user selected in a specific restaurant with their quantities and prices.
+	items []Item
 
 	// state states for Order State.
-	//
-	// Every State can go into Canceled State. But the only way where Canceled can go into is Closed.
-	// Canceled -> Closed
-	//
-	// State machine for an order:
-	// Created -> Paid -> Cooking -> Finished -> WaitingForCourier -> CourierTook -> Delivering -> Delivered -> Closed.
-	//
+	// Allowed transitions are described by StateMachine, see DefaultStateMachine.
 	state State
 
+	// history contains every transition of the Order`s state in chronological order.
+	history []StateChange
+
+	// cancellation describes why and by whom Order has been canceled.
+	// It is zero if Order has not been canceled.
+	cancellation Cancellation
+
 	// transactionID represents payment transaction [uuid].
 	transactionID uuid.UUID
 
+	// refundID represents refund transaction [uuid] of canceled paid Order.
+	refundID uuid.UUID
+
+	// refundFailure states for why the last refund failed.
+	refundFailure string
+
+	// pricing contains monetary breakdown of the Order.
+	pricing Pricing
+
+	// paidAmount represents the amount that was actually charged from the customer.
+	paidAmount money.Money
+
+	// fulfillment states for how Order gets to the customer.
+	fulfillment Fulfillment
+
 	// destination contains geo position of where Order should be delivered.
+	// It is zero for pickup Order.
 	destination destination.Destination
 
+	// restaurantLocation contains geo position of the restaurant Order is prepared in.
+	restaurantLocation destination.Destination
+
+	// eta represents when Order is expected to reach the customer.
+	// It is re-estimated at every state transition, see Estimator.
+	eta time.Time
+
+	// deliverAt represents when customer wants Order to be delivered.
+	// It is zero if Order should be delivered as soon as possible.
+	deliverAt time.Time
+
+	// releaseAt represents when scheduled Order should move into the normal flow.
+	releaseAt time.Time
+
 	// createdAt represents where Order has been created.
 	createdAt time.Time
+
+	// version is managed by a repository to detect concurrent modifications of the same Order.
+	version int64
+
+	// changes contains events recorded since Order has been created or rebuilt.
+	changes []DomainEvent
 }
 
 func (o *Order) State() State             { return o.state }
 func (o *Order) TransactionID() uuid.UUID { return o.transactionID }
 func (o *Order) CourierID() uuid.UUID     { return o.courierID }
+func (o *Order) Version() int64           { return o.version }
 
 func (o *Order) ID() uuid.UUID {
 	return o.id
@@ -68,6 +111,90 @@ func (o *Order) CreateAt() time.Time {
 	return o.createdAt
 }
 
+// Items returns a copy of Order`s items.
+func (o *Order) Items() []Item {
+	items := make([]Item, len(o.items))
+	copy(items, o.items)
+
+	return items
+}
+
+// Meals returns [uuid] of every meal in the Order.
+func (o *Order) Meals() uuid.UUIDs {
+	meals := make(uuid.UUIDs, len(o.items))
+
+	for i, item := range o.items {
+		meals[i] = item.mealID
+	}
+
+	return meals
+}
+
+// History returns a copy of Order`s state transitions in chronological order.
+func (o *Order) History() []StateChange {
+	history := make([]StateChange, len(o.history))
+	copy(history, o.history)
+
+	return history
+}
+
+func (o *Order) Destination() destination.Destination { return o.destination }
+
+// Assignments returns every courier assignment of the Order in chronological order.
+func (o *Order) Assignments() []CourierAssignment {
+	assignments := make([]CourierAssignment, len(o.assignments))
+	copy(assignments, o.assignments)
+
+	return assignments
+}
+
+// AwaitingPaymentSince returns when Order has entered [Created] state it waits for payment in.
+// Scheduled Order enters it on release, which is later than it has been created.
+func (o *Order) AwaitingPaymentSince() time.Time {
+	for i := len(o.history) - 1; i >= 0; i-- {
+		if o.history[i].to == Created {
+			return o.history[i].at
+		}
+	}
+
+	if !o.releaseAt.IsZero() {
+		return o.releaseAt
+	}
+
+	return o.createdAt
+}
+
+func (o *Order) Cancellation() Cancellation  { return o.cancellation }
+func (o *Order) Fulfillment() Fulfillment    { return o.fulfillment }
+func (o *Order) ETA() time.Time              { return o.eta }
+func (o *Order) DeliverAt() time.Time        { return o.deliverAt }
+func (o *Order) ReleaseAt() time.Time        { return o.releaseAt }
+func (o *Order) RefundID() uuid.UUID         { return o.refundID }
+func (o *Order) RefundFailureReason() string { return o.refundFailure }
+
+func (o *Order) Pricing() Pricing        { return o.pricing }
+func (o *Order) PaidAmount() money.Money { return o.paidAmount }
+
+// Subtotal returns sum of all items` totals.
+func (o *Order) Subtotal() money.Money {
+	return o.pricing.Subtotal()
+}
+
+// Total returns the amount customer owes for the Order.
+func (o *Order) Total() money.Money {
+	return o.pricing.Total()
+}
+
+// Balance returns the amount customer owes in addition to the charged one, e.g. after items of paid Order are modified.
+// It is negative if customer is owed a refund and zero for Order which has not been paid.
+func (o *Order) Balance() money.Money {
+	if o.transactionID == uuid.Nil {
+		return money.New(0, o.pricing.Currency())
+	}
+
+	return money.New(o.Total().Amount()-o.paidAmount.Amount(), o.pricing.Currency())
+}
+
 // Is shows if Order`s state matching state.
 func (o *Order) Is(state State) bool {
 	return o.state == state
@@ -79,16 +206,43 @@ func (o *Order) ToEvent() *event.Type {
 		OrderID:      o.id.String(),
 		CustomerID:   o.customerID.String(),
 		RestaurantID: o.restaurantID.String(),
-		Meals:        o.meals.Strings(),
+		Meals:        o.Meals().Strings(),
+		Items:        itemsToEvent(o.items),
+		Pricing:      pricingToEvent(o.pricing),
+		Balance:      o.Balance(),
+		Fulfillment:  o.fulfillment.String(),
 		Destination:  o.destination,
+		DeliverAt:    o.deliverAt,
+		ETA:          o.eta,
+	}
+}
+
+// Option configures optional Order properties.
+type Option func(o *Order) error
+
+// WithAddress sets address of Order destination. Pickup Order ignores the address.
+func WithAddress(p destination.AddressParams) Option {
+	return func(o *Order) error {
+		address, err := destination.NewAddress(p)
+		if err != nil {
+			return errors.WithMessage(err, "cannot resolve address")
+		}
+
+		o.destination = o.destination.WithAddress(address)
+
+		return nil
 	}
 }
 
 // NewOrder creates new Order.
+// Order is [Created] unless it is scheduled with [WithDeliveryTime].
+// Order is delivered to the destination unless it is picked up, see [WithFulfillment].
 func NewOrder(
 	restaurantID, userID string,
-	mealsIDs []string,
+	itemsParams []ItemParams,
+	charges Charges,
 	latitude, longitude float64,
+	opts ...Option,
 ) (*Order, error) {
 	var errs error
 
@@ -104,56 +258,92 @@ func NewOrder(
 			errors.WithMessage(err, "cannot parse user id"))
 	}
 
-	meals, err := mealsID(mealsIDs)
+	items, err := newItems(itemsParams)
 	if err != nil {
 		errs = multierror.Append(errs,
-			errors.WithMessage(err, "cannot parse meals` id"))
+			errors.WithMessage(err, "cannot resolve items"))
 	}
 
-	deliverTo, err := destination.NewDestination(latitude, longitude)
+	pricing, err := newPricing(items, charges)
 	if err != nil {
 		errs = multierror.Append(errs,
-			errors.WithMessage(err, "cannot resolve destination"))
+			errors.WithMessage(err, "cannot resolve pricing"))
 	}
 
-	if errs != nil {
-		return nil, errs
-	}
-
-	return &Order{
+	o := &Order{
 		id:            uuid.New(),
 		restaurantID:  rid,
 		customerID:    uid,
 		courierID:     uuid.Nil,
-		meals:         meals,
+		items:         items,
 		state:         Created,
 		transactionID: uuid.Nil,
-		destination:   deliverTo,
+		pricing:       pricing,
+		fulfillment:   FulfillmentDelivery,
 		createdAt:     time.Now(),
-	}, nil
-}
-
-// mealsID convert provided ids in slice of MealID.
-// If one error occurred while converting - an error returned.
-func mealsID(ids []string) (uuid.UUIDs, error) {
-	var (
-		errs    = make([]error, 0, len(ids))
-		mealIDs = make(uuid.UUIDs, len(ids))
-	)
-
-	for i, id := range ids {
-		newID, err := uuid.Parse(id)
-		switch err {
-		case nil:
-			mealIDs[i] = newID
-		default:
-			errs = append(errs, errors.Wrapf(err, "invalid meal id on index: %d", i))
+	}
+
+	for _, opt := range opts {
+		if err = opt(o); err != nil {
+			errs = multierror.Append(errs, err)
 		}
 	}
 
-	if len(errs) != 0 {
-		return nil, multierror.Append(nil, errs...)
+	switch o.fulfillment {
+	case FulfillmentDelivery:
+		deliverTo, dstErr := destination.NewDestination(latitude, longitude)
+		if dstErr != nil {
+			errs = multierror.Append(errs,
+				errors.WithMessage(dstErr, "cannot resolve destination"))
+		}
+
+		// address is set by WithAddress
+		o.destination = deliverTo.WithAddress(o.destination.Address())
+	default:
+		// pickup order has no destination
+		o.destination = destination.Destination{}
+	}
+
+	if errs != nil {
+		return nil, errs
 	}
 
-	return mealIDs, nil
+	if !o.deliverAt.IsZero() {
+		o.state = Scheduled
+	}
+
+	o.eta = DefaultETAEstimator.Estimate(o, o.createdAt)
+	o.history = []StateChange{newStateChange(State{}, o.state, ActorCustomer, "", o.eta)}
+	o.changes = []DomainEvent{OrderPlaced{Order: o.ToDatabaseDTO()}}
+
+	return o, nil
+}
+
+// itemsToEvent converts items to the event representation.
+func itemsToEvent(items []Item) []event.Item {
+	eventItems := make([]event.Item, len(items))
+
+	for i, item := range items {
+		eventItems[i] = event.Item{
+			MealID:    item.mealID.String(),
+			Quantity:  item.quantity,
+			UnitPrice: item.unitPrice,
+			Notes:     item.notes,
+		}
+	}
+
+	return eventItems
+}
+
+// pricingToEvent converts pricing to the event representation.
+func pricingToEvent(p Pricing) event.Pricing {
+	return event.Pricing{
+		Subtotal:    p.Subtotal(),
+		DeliveryFee: p.DeliveryFee(),
+		ServiceFee:  p.ServiceFee(),
+		Tax:         p.Tax(),
+		Tip:         p.Tip(),
+		Discount:    p.Discount(),
+		Total:       p.Total(),
+	}
 }
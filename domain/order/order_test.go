@@ -1 +1,336 @@
 package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"service/domain/shared/money"
+	"testing"
+	"time"
+)
+
+var testCharges = Charges{Currency: "USD"}
+
+func TestNewOrder_Items(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name string
+
+		items []ItemParams
+
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "OK",
+			items: []ItemParams{
+				{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 1000, Notes: "no onion"},
+			},
+		},
+		{
+			name:        "no items",
+			items:       nil,
+			wantErr:     true,
+			expectedErr: ErrNoItems,
+		},
+		{
+			name: "zero quantity",
+			items: []ItemParams{
+				{MealID: uuid.NewString(), Quantity: 0, UnitPrice: 1000},
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidItemQuantity,
+		},
+		{
+			name: "too big quantity",
+			items: []ItemParams{
+				{MealID: uuid.NewString(), Quantity: MaxItemQuantity + 1, UnitPrice: 1000},
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidItemQuantity,
+		},
+		{
+			name: "negative unit price",
+			items: []ItemParams{
+				{MealID: uuid.NewString(), Quantity: 1, UnitPrice: -1},
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidItemUnitPrice,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewOrder(uuid.NewString(), uuid.NewString(), tc.items, testCharges, 0.0, 0.0)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestOrder_Subtotal(t *testing.T) {
+	t.Run("assert subtotal sums items totals", func(t *testing.T) {
+		o, err := NewOrder(
+			uuid.NewString(),
+			uuid.NewString(),
+			[]ItemParams{
+				{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+				{MealID: uuid.NewString(), Quantity: 3, UnitPrice: 250},
+			},
+			testCharges,
+			0.0,
+			0.0,
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, money.New(1750, "USD"), o.Subtotal())
+		assert.Equal(t, o.Subtotal(), o.Total())
+	})
+}
+
+func TestNewOrder_Pricing(t *testing.T) {
+	items := []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 1000},
+	}
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		charges Charges
+
+		wantErr     bool
+		expectedErr error
+
+		total money.Money
+	}{
+		{
+			name: "OK",
+			charges: Charges{
+				Currency:    "EUR",
+				DeliveryFee: 300,
+				ServiceFee:  100,
+				Tax:         240,
+				Tip:         150,
+				Discount:    500,
+			},
+			total: money.New(2290, "EUR"),
+		},
+		{
+			name:        "invalid currency",
+			charges:     Charges{Currency: "euro"},
+			wantErr:     true,
+			expectedErr: money.ErrInvalidCurrency,
+		},
+		{
+			name:        "negative fee",
+			charges:     Charges{Currency: "EUR", DeliveryFee: -1},
+			wantErr:     true,
+			expectedErr: ErrNegativeCharge,
+		},
+		{
+			name:        "discount exceeds total",
+			charges:     Charges{Currency: "EUR", Discount: 2001},
+			wantErr:     true,
+			expectedErr: ErrDiscountExceedsTotal,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, tc.charges, 0.0, 0.0)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.total, o.Total())
+		})
+	}
+}
+
+func TestNewOrder_Scheduled(t *testing.T) {
+	items := []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+	}
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		deliverAt time.Time
+
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name:      "OK",
+			deliverAt: time.Now().Add(2 * time.Hour),
+		},
+		{
+			name:        "too soon",
+			deliverAt:   time.Now().Add(MinScheduleLeadTime - time.Minute),
+			wantErr:     true,
+			expectedErr: ErrDeliveryTimeTooSoon,
+		},
+		{
+			name:        "in the past",
+			deliverAt:   time.Now().Add(-time.Hour),
+			wantErr:     true,
+			expectedErr: ErrDeliveryTimeTooSoon,
+		},
+		{
+			name:        "too far",
+			deliverAt:   time.Now().Add(MaxScheduleHorizon + time.Hour),
+			wantErr:     true,
+			expectedErr: ErrDeliveryTimeTooFar,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0,
+				WithDeliveryTime(tc.deliverAt))
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, Scheduled, o.State())
+			assert.Equal(t, tc.deliverAt, o.DeliverAt())
+			assert.Equal(t, tc.deliverAt.Add(-MinScheduleLeadTime), o.ReleaseAt())
+			require.Len(t, o.History(), 1)
+			assert.Equal(t, Scheduled, o.History()[0].To())
+		})
+	}
+
+	t.Run("assert order without delivery time is created", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0)
+
+		require.NoError(t, err)
+		assert.Equal(t, Created, o.State())
+		assert.True(t, o.DeliverAt().IsZero())
+	})
+}
+
+func TestOrder_AwaitingPaymentSince(t *testing.T) {
+	items := []ItemParams{{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000}}
+
+	t.Run("assert order waits for payment since it has been created", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0)
+		require.NoError(t, err)
+
+		assert.Equal(t, o.History()[0].At(), o.AwaitingPaymentSince())
+	})
+
+	t.Run("assert scheduled order waits for payment since it has been released", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0,
+			WithDeliveryTime(time.Now().Add(2*time.Hour)))
+		require.NoError(t, err)
+
+		assert.Equal(t, o.ReleaseAt(), o.AwaitingPaymentSince())
+
+		_, err = NewStateOperator(o).ReleaseOrder()
+		require.NoError(t, err)
+
+		history := o.History()
+		require.Len(t, history, 2)
+		assert.Equal(t, history[1].At(), o.AwaitingPaymentSince())
+		assert.True(t, o.AwaitingPaymentSince().After(o.CreateAt()))
+	})
+}
+
+func TestNewOrder_Fulfillment(t *testing.T) {
+	items := []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+	}
+
+	t.Run("assert order is delivered by default", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 10.0, 20.0)
+
+		require.NoError(t, err)
+		assert.Equal(t, FulfillmentDelivery, o.Fulfillment())
+	})
+
+	t.Run("assert pickup order does not require destination", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 100.0, 200.0,
+			WithFulfillment(FulfillmentPickup))
+
+		require.NoError(t, err)
+		assert.Equal(t, FulfillmentPickup, o.Fulfillment())
+		assert.Zero(t, o.destination)
+	})
+
+	t.Run("assert delivery order requires valid destination", func(t *testing.T) {
+		_, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 100.0, 20.0,
+			WithFulfillment(FulfillmentDelivery))
+
+		assert.ErrorIs(t, err, destination.ErrInvalidLatitude)
+	})
+
+	t.Run("assert unknown fulfillment is rejected", func(t *testing.T) {
+		_, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0,
+			WithFulfillment("drone"))
+
+		assert.ErrorIs(t, err, ErrInvalidFulfillment)
+	})
+}
+
+func TestNewOrder_Address(t *testing.T) {
+	items := []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+	}
+
+	address := destination.AddressParams{
+		Line1:        "Khreshchatyk St, 22",
+		City:         "Kyiv",
+		Apartment:    "14",
+		Instructions: "call on arrival",
+		Contactless:  true,
+	}
+
+	t.Run("assert address is kept in destination", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 50.45, 30.52,
+			WithAddress(address))
+
+		require.NoError(t, err)
+		assert.Equal(t, 50.45, o.Destination().Latitude())
+		assert.Equal(t, "Khreshchatyk St, 22", o.Destination().Address().Line1())
+		assert.True(t, o.Destination().Address().Contactless())
+
+		restored := o.ToDatabaseDTO().ToOrder()
+		assert.Equal(t, o.Destination(), restored.Destination())
+	})
+
+	t.Run("assert invalid address is rejected", func(t *testing.T) {
+		_, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 50.45, 30.52,
+			WithAddress(destination.AddressParams{City: "Kyiv"}))
+
+		assert.ErrorIs(t, err, destination.ErrEmptyAddressLine)
+	})
+
+	t.Run("assert pickup order ignores address", func(t *testing.T) {
+		o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, 0.0, 0.0,
+			WithFulfillment(FulfillmentPickup), WithAddress(address))
+
+		require.NoError(t, err)
+		assert.True(t, o.Destination().Address().IsZero())
+	})
+}
+
+func TestOrder_Version(t *testing.T) {
+	o, err := NewOrder(uuid.NewString(), uuid.NewString(), []ItemParams{
+		{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+	}, testCharges, 10.0, 20.0)
+	require.NoError(t, err)
+
+	assert.Zero(t, o.Version(), "new order must not have been saved yet")
+
+	dto := o.ToDatabaseDTO()
+	dto.Version = 7
+
+	assert.Equal(t, int64(7), dto.ToOrder().Version())
+}
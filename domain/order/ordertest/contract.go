@@ -0,0 +1,271 @@
+// Package ordertest provides tests every [order.Repository] implementation must pass
+// and fixtures shared by tests of orders.
+//
+//	func TestRepository(t *testing.T) {
+//		ordertest.RepositoryContract(t, func(t *testing.T) order.Repository {
+//			return NewRepository(...)
+//		})
+//	}
+package ordertest
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	"service/infrastructure/outbox"
+	"sync"
+	"testing"
+	"time"
+)
+
+// RepositoryContract runs contract tests against repositories created by newRepository.
+func RepositoryContract(t *testing.T, newRepository func(t *testing.T) order.Repository) {
+	t.Helper()
+
+	ctx := context.Background()
+
+	t.Run("assert created order can be got", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assertSameOrder(t, o, got)
+	})
+
+	t.Run("assert unknown order is not found", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.Get(ctx, uuid.New())
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+
+		err = repository.Operate(ctx, uuid.New(), func(*order.Order) error { return nil })
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+	})
+
+	t.Run("assert operated order is stored", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+		transactionID := uuid.New()
+
+		require.NoError(t, repository.Create(ctx, o))
+
+		err := repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := order.NewStateOperator(o).PayOrder(transactionID, o.Total())
+			return err
+		})
+		require.NoError(t, err)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Paid, got.State())
+		assert.Equal(t, transactionID, got.TransactionID())
+		assert.Equal(t, o.Total(), got.PaidAmount())
+		assert.Len(t, got.History(), 2)
+		assert.Greater(t, got.Version(), o.Version())
+	})
+
+	t.Run("assert removed items are not stored", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+
+		mealID := uuid.New()
+		err := repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := order.NewStateOperator(o).ModifyItems([]order.ItemParams{
+				{MealID: mealID.String(), Quantity: 3, UnitPrice: 700},
+			})
+			return err
+		})
+		require.NoError(t, err)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		require.Len(t, got.Items(), 1)
+		assert.Equal(t, mealID, got.Items()[0].MealID())
+		assert.Equal(t, int64(2100), got.Subtotal().Amount())
+	})
+
+	t.Run("assert released courier assignment is stored", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+		first, second := uuid.New(), uuid.New()
+
+		require.NoError(t, repository.Create(ctx, o))
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			return takeByCourier(o, first)
+		}))
+
+		err := repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := order.NewStateOperator(o).ReassignCourier(second, "courier is late")
+			return err
+		})
+		require.NoError(t, err)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		require.Len(t, got.Assignments(), 2)
+		assert.Equal(t, first, got.Assignments()[0].CourierID())
+		assert.False(t, got.Assignments()[0].Active())
+		assert.Equal(t, second, got.CourierID())
+		assert.Len(t, got.History(), 6)
+	})
+
+	t.Run("assert operation without changes is not stored", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+
+		before, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(*order.Order) error { return nil }))
+
+		if withOutbox, ok := repository.(outbox.Repository); ok {
+			written := false
+
+			err = withOutbox.OperateWith(ctx, o.ID(), func(*order.Order) error { return nil },
+				outbox.WriterFunc(func(context.Context, message.Publisher, *order.Order) error {
+					written = true
+					return nil
+				}))
+			require.NoError(t, err)
+
+			assert.False(t, written, "writer must not be called if operation records no changes")
+		}
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, before.Version(), got.Version())
+		assert.Len(t, got.History(), len(before.History()))
+	})
+
+	t.Run("assert failed operation is not stored", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+
+		err := repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			if _, err := order.NewStateOperator(o).CookOrder(); err != nil {
+				return err
+			}
+
+			return assert.AnError
+		})
+		require.Error(t, err)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Created, got.State())
+	})
+
+	t.Run("assert concurrent operations are not lost", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			return takeByCourier(o, uuid.New())
+		}))
+
+		const couriers = 5
+
+		var (
+			wg        sync.WaitGroup
+			mu        sync.Mutex
+			succeeded int
+		)
+
+		for i := 0; i < couriers; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				err := repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+					_, err := order.NewStateOperator(o).ReassignCourier(uuid.New(), "courier is late")
+					return err
+				})
+				if err != nil {
+					assert.ErrorIs(t, err, order.ErrConcurrentModification)
+					return
+				}
+
+				mu.Lock()
+				succeeded++
+				mu.Unlock()
+			}()
+		}
+
+		wg.Wait()
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Positive(t, succeeded)
+		assert.Len(t, got.Assignments(), succeeded+1, "every successful operation must be stored")
+	})
+
+	t.Run("assert deleted order is not found", func(t *testing.T) {
+		repository := newRepository(t)
+		o := NewOrder(t)
+
+		require.NoError(t, repository.Create(ctx, o))
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			return takeByCourier(o, uuid.New())
+		}))
+		require.NoError(t, repository.Delete(ctx, o))
+
+		_, err := repository.Get(ctx, o.ID())
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+	})
+}
+
+// takeByCourier moves order through cooking to the courier.
+func takeByCourier(o *order.Order, courierID uuid.UUID) error {
+	operator := order.NewStateOperator(o)
+
+	if _, err := operator.PayOrder(uuid.New(), o.Total()); err != nil {
+		return err
+	}
+
+	for _, step := range []func() (bool, error){operator.CookOrder, operator.OrderFinished, operator.WaitForCourier} {
+		if _, err := step(); err != nil {
+			return err
+		}
+	}
+
+	_, err := operator.CourierTookOrder(courierID)
+
+	return err
+}
+
+func assertSameOrder(t *testing.T, expected, actual *order.Order) {
+	t.Helper()
+
+	assert.Equal(t, expected.ID(), actual.ID())
+	assert.Equal(t, expected.RestaurantID(), actual.RestaurantID())
+	assert.Equal(t, expected.CustomerID(), actual.CustomerID())
+	assert.Equal(t, expected.State(), actual.State())
+	assert.Equal(t, expected.Items(), actual.Items())
+	assert.Equal(t, expected.Pricing(), actual.Pricing())
+	assert.Equal(t, expected.Fulfillment(), actual.Fulfillment())
+	assert.InDelta(t, expected.Destination().Latitude(), actual.Destination().Latitude(), 1e-9)
+	assert.InDelta(t, expected.Destination().Longitude(), actual.Destination().Longitude(), 1e-9)
+	assert.WithinDuration(t, expected.CreateAt(), actual.CreateAt(), time.Millisecond)
+	assert.Len(t, actual.History(), len(expected.History()))
+}
@@ -0,0 +1,36 @@
+package ordertest
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	"testing"
+	"time"
+)
+
+// NewOrder creates a valid delivery order configured with opts.
+func NewOrder(t *testing.T, opts ...order.Option) *order.Order {
+	t.Helper()
+
+	return NewCustomerOrder(t, uuid.NewString(), opts...)
+}
+
+// NewCustomerOrder creates a valid delivery order of customer configured with opts.
+func NewCustomerOrder(t *testing.T, customerID string, opts ...order.Option) *order.Order {
+	t.Helper()
+
+	o, err := order.NewOrder(uuid.NewString(), customerID, []order.ItemParams{
+		{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000},
+		{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 550},
+	}, order.Charges{Currency: "USD", DeliveryFee: 300}, 50.45, 30.52, opts...)
+	require.NoError(t, err)
+
+	return o
+}
+
+// NewScheduledOrder creates a valid delivery order scheduled to be delivered in two hours.
+func NewScheduledOrder(t *testing.T) *order.Order {
+	t.Helper()
+
+	return NewOrder(t, order.WithDeliveryTime(time.Now().Add(2*time.Hour)))
+}
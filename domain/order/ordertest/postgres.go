@@ -0,0 +1,26 @@
+package ordertest
+
+import (
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"os"
+	"testing"
+)
+
+// OpenDB connects to the database provided by TEST_POSTGRES_DSN. Test is skipped if it is not set.
+// Schemes are initialized by the caller.
+func OpenDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_POSTGRES_DSN")
+	if dsn == "" {
+		t.Skip("TEST_POSTGRES_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
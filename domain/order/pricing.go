@@ -0,0 +1,125 @@
+package order
+
+import (
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"service/domain/shared/money"
+)
+
+var (
+	ErrNegativeCharge       = errors.New("invalid charge: must not be negative")
+	ErrDiscountExceedsTotal = errors.New("invalid discount: must not exceed order total")
+)
+
+// Charges contains raw Order charges in minor units of Currency. Used to create a Pricing.
+type Charges struct {
+	Currency    string
+	DeliveryFee int64
+	ServiceFee  int64
+	Tax         int64
+	Tip         int64
+	Discount    int64
+}
+
+// Pricing represents monetary breakdown of an Order. All amounts are in minor units of currency.
+// Pricing is a value object.
+type Pricing struct { //nolint:govet
+	// currency states for currency of every amount in the Pricing.
+	currency money.Currency
+
+	// subtotal states for sum of all Order`s items totals.
+	subtotal int64
+
+	deliveryFee int64
+	serviceFee  int64
+	tax         int64
+	tip         int64
+	discount    int64
+}
+
+func (p Pricing) Currency() money.Currency { return p.currency }
+func (p Pricing) Subtotal() money.Money    { return money.New(p.subtotal, p.currency) }
+func (p Pricing) DeliveryFee() money.Money { return money.New(p.deliveryFee, p.currency) }
+func (p Pricing) ServiceFee() money.Money  { return money.New(p.serviceFee, p.currency) }
+func (p Pricing) Tax() money.Money         { return money.New(p.tax, p.currency) }
+func (p Pricing) Tip() money.Money         { return money.New(p.tip, p.currency) }
+func (p Pricing) Discount() money.Money    { return money.New(p.discount, p.currency) }
+
+// Total returns the grand total customer owes:
+// subtotal + delivery fee + service fee + tax + tip - discount.
+func (p Pricing) Total() money.Money {
+	return money.New(p.total(), p.currency)
+}
+
+func (p Pricing) total() int64 {
+	return p.subtotal + p.deliveryFee + p.serviceFee + p.tax + p.tip - p.discount
+}
+
+// newPricing creates Pricing for items with provided charges.
+func newPricing(items []Item, c Charges) (Pricing, error) {
+	var errs error
+
+	currency, err := money.ParseCurrency(c.Currency)
+	if err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	charges := []struct {
+		name   string
+		amount int64
+	}{
+		{"delivery fee", c.DeliveryFee},
+		{"service fee", c.ServiceFee},
+		{"tax", c.Tax},
+		{"tip", c.Tip},
+		{"discount", c.Discount},
+	}
+
+	for _, charge := range charges {
+		if charge.amount < 0 {
+			errs = multierror.Append(errs, errors.Wrap(ErrNegativeCharge, charge.name))
+		}
+	}
+
+	if errs != nil {
+		return Pricing{}, errs
+	}
+
+	p := Pricing{
+		currency:    currency,
+		subtotal:    subtotal(items),
+		deliveryFee: c.DeliveryFee,
+		serviceFee:  c.ServiceFee,
+		tax:         c.Tax,
+		tip:         c.Tip,
+		discount:    c.Discount,
+	}
+
+	if p.total() < 0 {
+		return Pricing{}, ErrDiscountExceedsTotal
+	}
+
+	return p, nil
+}
+
+// withItems returns copy of Pricing recalculated for provided items.
+func (p Pricing) withItems(items []Item) (Pricing, error) {
+	p.subtotal = subtotal(items)
+
+	if p.total() < 0 {
+		return Pricing{}, ErrDiscountExceedsTotal
+	}
+
+	return p, nil
+}
+
+// subtotal returns sum of all items` totals.
+func subtotal(items []Item) int64 {
+	var sum int64
+
+	for _, item := range items {
+		sum += item.Total()
+	}
+
+	return sum
+}
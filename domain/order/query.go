@@ -0,0 +1,141 @@
+package order
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	// DefaultListLimit is how many orders are listed if limit is not provided.
+	DefaultListLimit = 50
+
+	// MaxListLimit is the maximum number of orders listed at once.
+	MaxListLimit = 200
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSort      = errors.New("invalid sort")
+	ErrInvalidListLimit = errors.Errorf("invalid limit: must be between 1 and %d", MaxListLimit)
+	ErrInvalidTimeRange = errors.New("invalid created at range: from must be before to")
+)
+
+// Sort describes order orders are listed in. Orders created at the same time are ordered by their id.
+type Sort string
+
+const (
+	SortCreatedAtDesc Sort = "-created_at"
+	SortCreatedAtAsc  Sort = "created_at"
+)
+
+// ParseSort returns Sort by its name. Empty name means [SortCreatedAtDesc].
+func ParseSort(name string) (Sort, error) {
+	switch Sort(name) {
+	case "", SortCreatedAtDesc:
+		return SortCreatedAtDesc, nil
+	case SortCreatedAtAsc:
+		return SortCreatedAtAsc, nil
+	default:
+		return "", errors.Wrapf(ErrInvalidSort, "unknown sort: %s", name)
+	}
+}
+
+// Filter selects orders. Zero fields do not restrict the selection.
+type Filter struct { //nolint:govet
+	CustomerID   uuid.UUID
+	RestaurantID uuid.UUID
+	CourierID    uuid.UUID
+
+	// States selects orders in any of listed states.
+	States []State
+
+	// CreatedFrom and CreatedTo select orders created in [CreatedFrom, CreatedTo).
+	CreatedFrom time.Time
+	CreatedTo   time.Time
+}
+
+// Cursor points at the last order of a listed page. The next page starts right after it.
+type Cursor struct { //nolint:govet
+	Sort      Sort      `json:"s"`
+	CreatedAt time.Time `json:"c"`
+	ID        uuid.UUID `json:"i"`
+}
+
+// String encodes Cursor into an opaque string, see [ParseCursor].
+func (c Cursor) String() string {
+	bytes, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
+
+// ParseCursor decodes Cursor encoded by [Cursor.String].
+func ParseCursor(s string) (Cursor, error) {
+	bytes, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	var c Cursor
+	if err = json.Unmarshal(bytes, &c); err != nil {
+		return Cursor{}, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+
+	if _, err = ParseSort(string(c.Sort)); err != nil || c.ID == uuid.Nil {
+		return Cursor{}, errors.Wrap(ErrInvalidCursor, "malformed cursor")
+	}
+
+	return c, nil
+}
+
+// ListQuery describes a page of orders to list.
+type ListQuery struct { //nolint:govet
+	Filter Filter
+	Sort   Sort
+
+	// After is the cursor of the previous page. The first page is listed if it is nil.
+	After *Cursor
+
+	Limit int
+}
+
+// Validate checks if ListQuery can be listed.
+func (q ListQuery) Validate() error {
+	var errs error
+
+	if _, err := ParseSort(string(q.Sort)); err != nil {
+		errs = multierror.Append(errs, err)
+	}
+
+	if q.Limit <= 0 || q.Limit > MaxListLimit {
+		errs = multierror.Append(errs, ErrInvalidListLimit)
+	}
+
+	if !q.Filter.CreatedFrom.IsZero() && !q.Filter.CreatedTo.IsZero() && !q.Filter.CreatedFrom.Before(q.Filter.CreatedTo) {
+		errs = multierror.Append(errs, ErrInvalidTimeRange)
+	}
+
+	if q.After != nil && q.After.Sort != q.Sort {
+		errs = multierror.Append(errs, errors.Wrap(ErrInvalidCursor, "cursor has been issued for another sort"))
+	}
+
+	return errs
+}
+
+// Page is a listed page of orders.
+type Page struct {
+	Orders []*Order
+
+	// Next is the cursor of the next page. It is nil if there are no more orders.
+	Next *Cursor
+}
+
+// Querier lists orders.
+type Querier interface {
+	// List returns a page of orders matching q. q must be valid, see [ListQuery.Validate].
+	List(ctx context.Context, q ListQuery) (Page, error)
+}
@@ -0,0 +1,95 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestCursor(t *testing.T) {
+	t.Run("assert cursor is decoded as encoded", func(t *testing.T) {
+		c := Cursor{Sort: SortCreatedAtAsc, CreatedAt: time.Now().UTC(), ID: uuid.New()}
+
+		parsed, err := ParseCursor(c.String())
+		require.NoError(t, err)
+
+		assert.Equal(t, c.Sort, parsed.Sort)
+		assert.Equal(t, c.ID, parsed.ID)
+		assert.True(t, c.CreatedAt.Equal(parsed.CreatedAt))
+	})
+
+	t.Run("assert malformed cursor is rejected", func(t *testing.T) {
+		for _, raw := range []string{"not a cursor", "e30", Cursor{Sort: "price"}.String()} {
+			_, err := ParseCursor(raw)
+			assert.ErrorIs(t, err, ErrInvalidCursor, raw)
+		}
+	})
+}
+
+func TestListQuery_Validate(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		q ListQuery
+
+		wantErr     bool
+		expectedErr error
+	}{
+		{
+			name: "OK",
+			q: ListQuery{
+				Filter: Filter{States: []State{Created, Paid}, CreatedFrom: now.Add(-time.Hour), CreatedTo: now},
+				Sort:   SortCreatedAtDesc,
+				After:  &Cursor{Sort: SortCreatedAtDesc, CreatedAt: now, ID: uuid.New()},
+				Limit:  DefaultListLimit,
+			},
+		},
+		{
+			name:        "unknown sort",
+			q:           ListQuery{Sort: "price", Limit: DefaultListLimit},
+			wantErr:     true,
+			expectedErr: ErrInvalidSort,
+		},
+		{
+			name:        "too big limit",
+			q:           ListQuery{Sort: SortCreatedAtDesc, Limit: MaxListLimit + 1},
+			wantErr:     true,
+			expectedErr: ErrInvalidListLimit,
+		},
+		{
+			name: "inverted time range",
+			q: ListQuery{
+				Filter: Filter{CreatedFrom: now, CreatedTo: now.Add(-time.Hour)},
+				Sort:   SortCreatedAtDesc,
+				Limit:  DefaultListLimit,
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidTimeRange,
+		},
+		{
+			name: "cursor of another sort",
+			q: ListQuery{
+				Sort:  SortCreatedAtAsc,
+				After: &Cursor{Sort: SortCreatedAtDesc, CreatedAt: now, ID: uuid.New()},
+				Limit: DefaultListLimit,
+			},
+			wantErr:     true,
+			expectedErr: ErrInvalidCursor,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.q.Validate()
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package order
 import (
 	"context"
 	"github.com/google/uuid"
+	"time"
 )
 
 type Operation func(*Order) error
@@ -13,3 +14,33 @@ type Repository interface {
 	Operate(ctx context.Context, id uuid.UUID, op Operation) error
 	Delete(ctx context.Context, o *Order) error
 }
+
+// ScheduledFinder finds scheduled orders.
+type ScheduledFinder interface {
+	// FindDueScheduled returns ids of at most limit [Scheduled] orders which should be released before provided time.
+	FindDueScheduled(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// UnpaidFinder finds orders waiting for payment.
+type UnpaidFinder interface {
+	// FindUnpaid returns ids of at most limit [Created] orders which have been waiting for payment since before provided time,
+	// see [Order.AwaitingPaymentSince].
+	FindUnpaid(ctx context.Context, awaitingBefore time.Time, limit int) ([]uuid.UUID, error)
+}
+
+// MessageKey identifies a message consumed by a handler.
+type MessageKey struct {
+	// MessageID states for the consumed message id.
+	MessageID string
+
+	// Handler states for the name of the handler that consumed the message.
+	Handler string
+}
+
+// OnceOperator operates Order at most once per consumed message.
+type OnceOperator interface {
+	// OperateOnce applies op to Order unless the message identified by key has been already processed.
+	// The message is recorded as processed in the same transaction op is applied in.
+	// It returns false if the message is a duplicate and op has not been applied.
+	OperateOnce(ctx context.Context, key MessageKey, id uuid.UUID, op Operation) (bool, error)
+}
@@ -0,0 +1,43 @@
+package order
+
+import (
+	"github.com/pkg/errors"
+	"time"
+)
+
+const (
+	// MinScheduleLeadTime is the minimum time between now and requested delivery time.
+	// Scheduled Order is released into the normal flow MinScheduleLeadTime before requested delivery time,
+	// so restaurant and courier have enough time to prepare and deliver it.
+	MinScheduleLeadTime = 45 * time.Minute
+
+	// MaxScheduleHorizon is the maximum time between now and requested delivery time.
+	MaxScheduleHorizon = 7 * 24 * time.Hour
+)
+
+var (
+	ErrDeliveryTimeTooSoon = errors.Errorf("requested delivery time is too soon: must be at least %s from now",
+		MinScheduleLeadTime)
+	ErrDeliveryTimeTooFar = errors.Errorf("requested delivery time is too far: must be at most %s from now",
+		MaxScheduleHorizon)
+)
+
+// WithDeliveryTime schedules Order to be delivered at provided time.
+// Scheduled Order waits in [Scheduled] state until it is released at [Order.ReleaseAt].
+func WithDeliveryTime(at time.Time) Option {
+	return func(o *Order) error {
+		lead := time.Until(at)
+
+		switch {
+		case lead < MinScheduleLeadTime:
+			return ErrDeliveryTimeTooSoon
+		case lead > MaxScheduleHorizon:
+			return ErrDeliveryTimeTooFar
+		}
+
+		o.deliverAt = at
+		o.releaseAt = at.Add(-MinScheduleLeadTime)
+
+		return nil
+	}
+}
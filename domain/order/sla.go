@@ -0,0 +1,76 @@
+package order
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"time"
+)
+
+var ErrInvalidMaxDwell = errors.New("invalid maximum dwell time: must be positive")
+
+// SLA maps State to the maximum time Order may stay in it. States which are not listed are not limited.
+type SLA map[State]time.Duration
+
+// NewSLA creates SLA from maximum dwell times by State names.
+func NewSLA(maxDwell map[string]time.Duration) (SLA, error) {
+	var errs error
+
+	sla := make(SLA, len(maxDwell))
+
+	for name, dwell := range maxDwell {
+		state, err := ParseState(name)
+		if err != nil {
+			errs = multierror.Append(errs, err)
+			continue
+		}
+
+		if dwell <= 0 {
+			errs = multierror.Append(errs, errors.Wrapf(ErrInvalidMaxDwell, "state %s", name))
+			continue
+		}
+
+		sla[state] = dwell
+	}
+
+	if errs != nil {
+		return nil, errs
+	}
+
+	return sla, nil
+}
+
+// Breach describes Order which has stayed in its State longer than SLA allows.
+type Breach struct { //nolint:govet
+	OrderID uuid.UUID
+	State   State
+
+	// ChangeID is the id of the transition Order has entered State with.
+	// Every stay of Order in State is breached at most once.
+	ChangeID  uuid.UUID
+	EnteredAt time.Time
+
+	// MaxDwell is the maximum time Order may stay in State at the moment breach is detected.
+	MaxDwell   time.Duration
+	DetectedAt time.Time
+}
+
+// Dwell returns how long Order had stayed in State when breach was detected.
+func (b Breach) Dwell() time.Duration {
+	return b.DetectedAt.Sub(b.EnteredAt)
+}
+
+// BreachRecorder records orders breaching SLA.
+type BreachRecorder interface {
+	// RecordBreaches records at most limit new breaches of orders which have stayed in state longer than maxDwell
+	// and returns the recorded breaches. Breach which has been already recorded is not returned again.
+	RecordBreaches(ctx context.Context, state State, maxDwell time.Duration, limit int) ([]Breach, error)
+}
+
+// BreachFinder finds orders breaching SLA.
+type BreachFinder interface {
+	// FindOpenBreaches returns at most limit recorded breaches of orders which are still in breached state,
+	// the longest breaching first.
+	FindOpenBreaches(ctx context.Context, limit int) ([]Breach, error)
+}
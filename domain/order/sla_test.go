@@ -0,0 +1,59 @@
+package order
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestNewSLA(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name string
+
+		maxDwell map[string]time.Duration
+
+		wantErr     bool
+		expectedErr error
+		expected    SLA
+	}{
+		{
+			name:     "OK",
+			maxDwell: map[string]time.Duration{"order.cooking": 45 * time.Minute, "order.waiting": 20 * time.Minute},
+			expected: SLA{Cooking: 45 * time.Minute, WaitingForCourier: 20 * time.Minute},
+		},
+		{
+			name:        "unknown state",
+			maxDwell:    map[string]time.Duration{"order.lost": time.Minute},
+			wantErr:     true,
+			expectedErr: ErrInvalidState,
+		},
+		{
+			name:        "not positive dwell",
+			maxDwell:    map[string]time.Duration{"order.cooking": 0},
+			wantErr:     true,
+			expectedErr: ErrInvalidMaxDwell,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			sla, err := NewSLA(tc.maxDwell)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, sla)
+		})
+	}
+}
+
+func TestBreach_Dwell(t *testing.T) {
+	entered := time.Now()
+
+	b := Breach{EnteredAt: entered, DetectedAt: entered.Add(50 * time.Minute)}
+
+	assert.Equal(t, 50*time.Minute, b.Dwell())
+}
@@ -1,44 +1,72 @@
 package order
 
+import (
+	"database/sql/driver"
+	"github.com/pkg/errors"
+)
+
 // State represents the current state of the order.
-// It contains the name of the state and the next state.
+// Transitions between states are described by a StateMachine.
 // State is a value object.
-type State struct { //nolint:govet
+type State struct {
 	Name string
-	Next *State
 }
 
 func (s State) String() string { return s.Name }
 
-// State machine for an order:
+// Default state machine for an order (see DefaultStateMachine):
 // Created -> Paid -> Cooking -> Finished -> WaitingForCourier -> CourierTook -> Delivering -> Delivered -> Closed.
 //
-// Every State can go into Canceled State. But the only way where Canceled can go into is Closed.
+// Pickup order is picked up by the customer instead of a courier:
+// Finished -> ReadyForPickup -> PickedUp -> Closed.
+//
+// Scheduled order goes into the flow when it is released:
+// Scheduled -> Created.
+//
+// Every State can go into Canceled State. Unpaid canceled order goes into Closed.
 // Canceled -> Closed.
+//
+// Paid canceled order is closed only after money is refunded:
+// Canceled -> RefundRequested -> Refunded -> Closed.
+// RefundRequested -> RefundFailed -> RefundRequested.
 var (
-	Canceled = State{"order.canceled", &Closed}
+	Canceled = State{"order.canceled"}
+
+	Scheduled = State{"order.scheduled"}
 
-	Created = State{"order.created", &Paid}
+	Created = State{"order.created"}
 
-	Paid = State{"order.paid", &Cooking}
+	Paid = State{"order.paid"}
 
-	Cooking = State{"order.cooking", &Finished}
+	Cooking = State{"order.cooking"}
 
-	Finished = State{"order.cooking.finished", &WaitingForCourier}
+	Finished = State{"order.cooking.finished"}
 
-	WaitingForCourier = State{"order.waiting", &CourierTook}
+	WaitingForCourier = State{"order.waiting"}
 
-	CourierTook = State{"order.taken", &Delivering}
+	CourierTook = State{"order.taken"}
 
-	Delivering = State{"order.delivering", &Delivered}
+	Delivering = State{"order.delivering"}
 
-	Delivered = State{"order.delivered", &Closed}
+	Delivered = State{"order.delivered"}
 
-	Closed = State{"order.closed", nil}
+	ReadyForPickup = State{"order.pickup.ready"}
+
+	PickedUp = State{"order.picked.up"}
+
+	Closed = State{"order.closed"}
+
+	RefundRequested = State{"order.refund.requested"}
+
+	Refunded = State{"order.refunded"}
+
+	RefundFailed = State{"order.refund.failed"}
 )
 
 // mapStates is a map of order state names and order states.
-var mapStates = map[string]State{ //nolint:unused
+var mapStates = map[string]State{
+	"order.canceled":         Canceled,
+	"order.scheduled":        Scheduled,
 	"order.created":          Created,
 	"order.paid":             Paid,
 	"order.cooking":          Cooking,
@@ -47,5 +75,54 @@ var mapStates = map[string]State{ //nolint:unused
 	"order.taken":            CourierTook,
 	"order.delivering":       Delivering,
 	"order.delivered":        Delivered,
+	"order.pickup.ready":     ReadyForPickup,
+	"order.picked.up":        PickedUp,
 	"order.closed":           Closed,
+	"order.refund.requested": RefundRequested,
+	"order.refunded":         Refunded,
+	"order.refund.failed":    RefundFailed,
+}
+
+// ParseState returns State by its name.
+func ParseState(name string) (State, error) {
+	state, ok := mapStates[name]
+	if !ok {
+		return State{}, errors.Wrapf(ErrInvalidState, "unknown state: %s", name)
+	}
+
+	return state, nil
+}
+
+// Value implements [driver.Valuer]. State is stored by its name.
+func (s State) Value() (driver.Value, error) {
+	return s.Name, nil
+}
+
+// Scan implements [sql.Scanner]. Empty name is scanned into zero State.
+func (s *State) Scan(src any) error {
+	var name string
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		name = v
+	case []byte:
+		name = string(v)
+	default:
+		return errors.Errorf("cannot scan %T into state", src)
+	}
+
+	if name == "" {
+		*s = State{}
+		return nil
+	}
+
+	state, err := ParseState(name)
+	if err != nil {
+		return err
+	}
+
+	*s = state
+
+	return nil
 }
@@ -0,0 +1,46 @@
+package order
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestState_Scan(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name string
+
+		src any
+
+		wantErr  bool
+		expected State
+	}{
+		{name: "string", src: "order.paid", expected: Paid},
+		{name: "bytes", src: []byte("order.canceled"), expected: Canceled},
+		{name: "empty", src: "", expected: State{}},
+		{name: "nil", src: nil, expected: State{}},
+		{name: "unknown", src: "order.unknown", wantErr: true},
+		{name: "unsupported type", src: 1, wantErr: true},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			var state State
+
+			err := state.Scan(tc.src)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, state)
+		})
+	}
+}
+
+func TestState_Value(t *testing.T) {
+	value, err := Delivering.Value()
+
+	assert.NoError(t, err)
+	assert.Equal(t, Delivering.Name, value)
+}
@@ -0,0 +1,23 @@
+package order
+
+import (
+	"github.com/pkg/errors"
+	"service/domain/shared/zone"
+)
+
+var ErrOutsideDeliveryZone = errors.New("destination is outside of restaurant delivery zone")
+
+// CheckDeliveryZone checks if Order destination is inside the restaurant delivery zone.
+// Pickup Order is not delivered, so it is never outside the zone.
+func (o *Order) CheckDeliveryZone(z zone.Zone) error {
+	if o.fulfillment == FulfillmentPickup {
+		return nil
+	}
+
+	if !z.Contains(o.destination) {
+		return errors.Wrapf(ErrOutsideDeliveryZone, "latitude: %f, longitude: %f",
+			o.destination.Latitude(), o.destination.Longitude())
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package order
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"service/domain/shared/zone"
+	"testing"
+)
+
+func TestOrder_CheckDeliveryZone(t *testing.T) {
+	center, err := destination.NewDestination(50.0, 30.0)
+	require.NoError(t, err)
+
+	z, err := zone.NewZone(center, 10)
+	require.NoError(t, err)
+
+	items := []ItemParams{{MealID: uuid.NewString(), Quantity: 1, UnitPrice: 1000}}
+
+	testCases := []struct { //nolint:govet
+		name        string
+		lat, long   float64
+		opts        []Option
+		expectedErr error
+	}{
+		{"inside", 50.05, 30.05, nil, nil},
+		{"outside", 51.0, 30.0, nil, ErrOutsideDeliveryZone},
+		{"pickup", 0, 0, []Option{WithFulfillment(FulfillmentPickup)}, nil},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			o, err := NewOrder(uuid.NewString(), uuid.NewString(), items, testCharges, tc.lat, tc.long, tc.opts...)
+			require.NoError(t, err)
+
+			assert.ErrorIs(t, o.CheckDeliveryZone(z), tc.expectedErr)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package destination
+
+import (
+	"fmt"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+)
+
+const (
+	MaxAddressLineLength  = 256
+	MaxCityLength         = 128
+	MaxPostalCodeLength   = 16
+	MaxAddressPartLength  = 16
+	MaxInstructionsLength = 512
+)
+
+var (
+	ErrEmptyAddressLine = errors.New("address line must not be empty")
+	ErrAddressTooLong   = errors.New("address field is too long")
+)
+
+// Address represents where exactly courier should bring the order.
+// Address is a value object.
+type Address struct {
+	// line1 states for street and building.
+	line1 string
+
+	// line2 states for additional address information, e.g. building block.
+	line2 string
+
+	city       string
+	postalCode string
+
+	entrance  string
+	floor     string
+	apartment string
+
+	// instructions contains customer`s free-text wishes for the courier.
+	instructions string
+
+	// contactless states for leaving the order at the door.
+	contactless bool
+}
+
+func (a Address) Line1() string        { return a.line1 }
+func (a Address) Line2() string        { return a.line2 }
+func (a Address) City() string         { return a.city }
+func (a Address) PostalCode() string   { return a.postalCode }
+func (a Address) Entrance() string     { return a.entrance }
+func (a Address) Floor() string        { return a.floor }
+func (a Address) Apartment() string    { return a.apartment }
+func (a Address) Instructions() string { return a.instructions }
+func (a Address) Contactless() bool    { return a.contactless }
+
+// IsZero shows if Address is empty.
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// AddressParams contains raw Address data. Used to create an Address.
+type AddressParams struct {
+	Line1        string
+	Line2        string
+	City         string
+	PostalCode   string
+	Entrance     string
+	Floor        string
+	Apartment    string
+	Instructions string
+	Contactless  bool
+}
+
+// NewAddress creates new Address. Line1 is required, other fields are optional.
+func NewAddress(p AddressParams) (Address, error) {
+	var errs error
+
+	if p.Line1 == "" {
+		errs = multierror.Append(errs, ErrEmptyAddressLine)
+	}
+
+	fields := []struct {
+		name   string
+		value  string
+		maxLen int
+	}{
+		{"line1", p.Line1, MaxAddressLineLength},
+		{"line2", p.Line2, MaxAddressLineLength},
+		{"city", p.City, MaxCityLength},
+		{"postal code", p.PostalCode, MaxPostalCodeLength},
+		{"entrance", p.Entrance, MaxAddressPartLength},
+		{"floor", p.Floor, MaxAddressPartLength},
+		{"apartment", p.Apartment, MaxAddressPartLength},
+		{"instructions", p.Instructions, MaxInstructionsLength},
+	}
+
+	for _, field := range fields {
+		if len([]rune(field.value)) > field.maxLen {
+			errs = multierror.Append(errs,
+				errors.Wrap(ErrAddressTooLong, fmt.Sprintf("%s: must be at most %d characters", field.name, field.maxLen)))
+		}
+	}
+
+	if errs != nil {
+		return Address{}, errs
+	}
+
+	return Address{
+		line1:        p.Line1,
+		line2:        p.Line2,
+		city:         p.City,
+		postalCode:   p.PostalCode,
+		entrance:     p.Entrance,
+		floor:        p.Floor,
+		apartment:    p.Apartment,
+		instructions: p.Instructions,
+		contactless:  p.Contactless,
+	}, nil
+}
@@ -0,0 +1,46 @@
+package destination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"strings"
+	"testing"
+)
+
+func TestNewAddress(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name        string
+		params      destination.AddressParams
+		expectedErr error
+	}{
+		{"OK", destination.AddressParams{Line1: "Khreshchatyk St, 22", City: "Kyiv", Floor: "3"}, nil},
+		{"empty line", destination.AddressParams{City: "Kyiv"}, destination.ErrEmptyAddressLine},
+		{
+			"too long postal code",
+			destination.AddressParams{Line1: "Khreshchatyk St, 22", PostalCode: strings.Repeat("1", destination.MaxPostalCodeLength+1)},
+			destination.ErrAddressTooLong,
+		},
+		{
+			"too long instructions",
+			destination.AddressParams{Line1: "Khreshchatyk St, 22", Instructions: strings.Repeat("a", destination.MaxInstructionsLength+1)},
+			destination.ErrAddressTooLong,
+		},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			a, err := destination.NewAddress(tc.params)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.params.Line1, a.Line1())
+			assert.Equal(t, tc.params.City, a.City())
+			assert.Equal(t, tc.params.Floor, a.Floor())
+		})
+	}
+}
@@ -1,11 +1,19 @@
 package destination
 
-import "errors"
+import (
+	"errors"
+	"math"
+)
+
+// EarthRadius is the mean radius of the Earth in kilometers.
+const EarthRadius = 6371.0
 
-// Destination represents position coordinates.
+// Destination represents position coordinates and, optionally, the Address at that position.
 type Destination struct {
 	latitude  float64
 	longitude float64
+
+	address Address
 }
 
 func (d Destination) Latitude() float64 {
@@ -13,7 +21,41 @@ func (d Destination) Latitude() float64 {
 }
 
 func (d Destination) Longitude() float64 {
-	return d.latitude
+	return d.longitude
+}
+
+func (d Destination) Address() Address {
+	return d.address
+}
+
+// WithAddress returns copy of Destination with provided Address.
+func (d Destination) WithAddress(a Address) Destination {
+	d.address = a
+	return d
+}
+
+// IsZero shows if Destination is empty.
+func (d Destination) IsZero() bool {
+	return d == Destination{}
+}
+
+// DistanceTo returns great-circle distance to other Destination in kilometers using the haversine formula.
+func (d Destination) DistanceTo(other Destination) float64 {
+	var (
+		lat1 = radians(d.latitude)
+		lat2 = radians(other.latitude)
+		dLat = lat2 - lat1
+		dLon = radians(other.longitude - d.longitude)
+	)
+
+	h := math.Pow(math.Sin(dLat/2), 2) +
+		math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+
+	return 2 * EarthRadius * math.Asin(math.Sqrt(h))
+}
+
+func radians(degrees float64) float64 {
+	return degrees * math.Pi / 180
 }
 
 var (
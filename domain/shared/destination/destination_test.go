@@ -0,0 +1,52 @@
+package destination_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"testing"
+)
+
+func TestNewDestination(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name        string
+		lat, long   float64
+		expectedErr error
+	}{
+		{"OK", 50.45, 30.52, nil},
+		{"invalid latitude", 91, 30.52, destination.ErrInvalidLatitude},
+		{"invalid longitude", 50.45, -181, destination.ErrInvalidLongitude},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			d, err := destination.NewDestination(tc.lat, tc.long)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.lat, d.Latitude())
+			assert.Equal(t, tc.long, d.Longitude())
+		})
+	}
+}
+
+func TestDestination_DistanceTo(t *testing.T) {
+	kyiv, err := destination.NewDestination(50.4501, 30.5234)
+	require.NoError(t, err)
+
+	lviv, err := destination.NewDestination(49.8397, 24.0297)
+	require.NoError(t, err)
+
+	t.Run("assert distance between cities", func(t *testing.T) {
+		assert.InDelta(t, 468.0, kyiv.DistanceTo(lviv), 2.0)
+		assert.InDelta(t, kyiv.DistanceTo(lviv), lviv.DistanceTo(kyiv), 1e-9)
+	})
+
+	t.Run("assert distance to itself is zero", func(t *testing.T) {
+		assert.Zero(t, kyiv.DistanceTo(kyiv))
+	})
+}
@@ -4,13 +4,47 @@ import "service/event"
 
 type JSONDestination struct {
 	event.Event
-	Latitude  float64 `json:"latitude"`
-	Longitude float64 `json:"longitude"`
+	Latitude  float64      `json:"latitude"`
+	Longitude float64      `json:"longitude"`
+	Address   *JSONAddress `json:"address,omitempty"`
+}
+
+type JSONAddress struct { //nolint:govet
+	Line1        string `json:"line1"`
+	Line2        string `json:"line2,omitempty"`
+	City         string `json:"city,omitempty"`
+	PostalCode   string `json:"postal_code,omitempty"`
+	Entrance     string `json:"entrance,omitempty"`
+	Floor        string `json:"floor,omitempty"`
+	Apartment    string `json:"apartment,omitempty"`
+	Instructions string `json:"instructions,omitempty"`
+	Contactless  bool   `json:"contactless"`
 }
 
 func (d Destination) ToJSON() JSONDestination {
-	return JSONDestination{
+	j := JSONDestination{
 		Latitude:  d.latitude,
 		Longitude: d.longitude,
 	}
+
+	if !d.address.IsZero() {
+		address := d.address.ToJSON()
+		j.Address = &address
+	}
+
+	return j
+}
+
+func (a Address) ToJSON() JSONAddress {
+	return JSONAddress{
+		Line1:        a.line1,
+		Line2:        a.line2,
+		City:         a.city,
+		PostalCode:   a.postalCode,
+		Entrance:     a.entrance,
+		Floor:        a.floor,
+		Apartment:    a.apartment,
+		Instructions: a.instructions,
+		Contactless:  a.contactless,
+	}
 }
@@ -0,0 +1,96 @@
+// Package idempotency describes keys clients make retried requests safe with.
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"time"
+)
+
+// MaxKeyLength is the maximum length of an idempotency key.
+const MaxKeyLength = 255
+
+var (
+	ErrInvalidKey          = errors.New("invalid idempotency key: must be from 1 to 255 characters")
+	ErrFingerprintMismatch = errors.New("idempotency key has been already used with another request")
+	ErrRequestInProgress   = errors.New("request with the same idempotency key is in progress")
+)
+
+// Key is an idempotency key scoped by the customer who sent it, so equal keys of different customers never collide.
+type Key struct {
+	CustomerID uuid.UUID
+	Value      string
+}
+
+func (k Key) String() string { return k.CustomerID.String() + "/" + k.Value }
+
+// Record is a request made with an idempotency key.
+// Reservation of the key is identified by its CreatedAt, so a reservation taken over once it has expired
+// can't be completed or released by the request which made it.
+type Record struct { //nolint:govet
+	Key         Key
+	Fingerprint string
+
+	// Response is the response of the completed request. It is nil while request is in progress.
+	Response []byte
+
+	CreatedAt time.Time
+}
+
+// Completed tells if response of the request has been stored.
+func (r Record) Completed() bool {
+	return r.Response != nil
+}
+
+// Check checks if request with fingerprint is a replay of the recorded request.
+// [ErrFingerprintMismatch] is returned if it is another request, [ErrRequestInProgress] if recorded one is not completed.
+func (r Record) Check(fingerprint string) error {
+	switch {
+	case r.Fingerprint != fingerprint:
+		return ErrFingerprintMismatch
+	case !r.Completed():
+		return ErrRequestInProgress
+	default:
+		return nil
+	}
+}
+
+// Store keeps requests made with idempotency keys.
+type Store interface {
+	// Reserve records request with fingerprint under key and returns true.
+	// Reservation which has not been completed in time is taken over, so key of the request
+	// which has never been completed nor released does not block retries forever.
+	// If key has been already reserved, recorded Record and false are returned.
+	Reserve(ctx context.Context, key Key, fingerprint string) (Record, bool, error)
+
+	// Complete stores response of the reserved request.
+	Complete(ctx context.Context, reserved Record, response []byte) error
+
+	// Release removes reservation of the request which has not been completed, so it may be retried.
+	Release(ctx context.Context, reserved Record) error
+}
+
+// ValidateKey checks if key may be used as an idempotency key.
+func ValidateKey(key string) error {
+	if key == "" || len(key) > MaxKeyLength {
+		return ErrInvalidKey
+	}
+
+	return nil
+}
+
+// Fingerprint returns fingerprint of the request. Requests with equal JSON representation have the same fingerprint.
+func Fingerprint(request any) (string, error) {
+	bytes, err := json.Marshal(request)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(bytes)
+
+	return hex.EncodeToString(sum[:]), nil
+}
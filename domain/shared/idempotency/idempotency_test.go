@@ -0,0 +1,55 @@
+package idempotency_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/idempotency"
+	"strings"
+	"testing"
+)
+
+func TestRecord_Check(t *testing.T) {
+	testCases := []struct { //nolint:govet
+		name        string
+		record      idempotency.Record
+		fingerprint string
+		err         error
+	}{
+		{"completed replay", idempotency.Record{Fingerprint: "a", Response: []byte(`{}`)}, "a", nil},
+		{"another request", idempotency.Record{Fingerprint: "a", Response: []byte(`{}`)}, "b", idempotency.ErrFingerprintMismatch},
+		{"in progress replay", idempotency.Record{Fingerprint: "a"}, "a", idempotency.ErrRequestInProgress},
+		{"in progress another request", idempotency.Record{Fingerprint: "a"}, "b", idempotency.ErrFingerprintMismatch},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorIs(t, tc.record.Check(tc.fingerprint), tc.err)
+		})
+	}
+}
+
+func TestValidateKey(t *testing.T) {
+	assert.NoError(t, idempotency.ValidateKey("6f1c0a5e-5d8b-4bb4-9a2b-0d9e1f3c7a11"))
+	assert.ErrorIs(t, idempotency.ValidateKey(""), idempotency.ErrInvalidKey)
+	assert.ErrorIs(t, idempotency.ValidateKey(strings.Repeat("k", idempotency.MaxKeyLength+1)), idempotency.ErrInvalidKey)
+}
+
+func TestFingerprint(t *testing.T) {
+	type request struct {
+		CustomerID string `json:"customer_id"`
+		Quantity   int    `json:"quantity"`
+	}
+
+	first, err := idempotency.Fingerprint(request{CustomerID: "c", Quantity: 1})
+	require.NoError(t, err)
+
+	same, err := idempotency.Fingerprint(request{CustomerID: "c", Quantity: 1})
+	require.NoError(t, err)
+
+	another, err := idempotency.Fingerprint(request{CustomerID: "c", Quantity: 2})
+	require.NoError(t, err)
+
+	assert.Equal(t, first, same)
+	assert.NotEqual(t, first, another)
+}
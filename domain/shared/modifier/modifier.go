@@ -0,0 +1,8 @@
+package modifier
+
+import "context"
+
+// Modifier applies modify to the entity found by id and returns the modified entity.
+type Modifier[ID comparable, T any] interface {
+	Modify(ctx context.Context, id ID, modify func(T) error) (T, error)
+}
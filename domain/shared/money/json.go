@@ -0,0 +1,23 @@
+package money
+
+type JSONMoney struct {
+	Currency string `json:"currency"`
+	Amount   int64  `json:"amount"`
+}
+
+func (m Money) ToJSON() JSONMoney {
+	return JSONMoney{
+		Currency: m.currency.String(),
+		Amount:   m.amount,
+	}
+}
+
+// ToMoney converts JSONMoney to Money validating currency.
+func (j JSONMoney) ToMoney() (Money, error) {
+	currency, err := ParseCurrency(j.Currency)
+	if err != nil {
+		return Money{}, err
+	}
+
+	return New(j.Amount, currency), nil
+}
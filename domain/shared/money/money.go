@@ -0,0 +1,89 @@
+package money
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCurrency  = errors.New("invalid currency: must be ISO 4217 alphabetic code")
+	ErrCurrencyMismatch = errors.New("currency mismatch")
+)
+
+// Currency represents ISO 4217 alphabetic currency code, e.g. USD.
+type Currency string
+
+func (c Currency) String() string { return string(c) }
+
+// ParseCurrency parses ISO 4217 alphabetic currency code.
+// Code must consist of three upper-case latin letters.
+func ParseCurrency(code string) (Currency, error) {
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+
+	return Currency(code), nil
+}
+
+// Money represents an amount of money in minor units of currency (e.g. cents).
+// Money is a value object.
+type Money struct {
+	currency Currency
+	amount   int64
+}
+
+// New creates Money of amount minor units of currency.
+func New(amount int64, currency Currency) Money {
+	return Money{
+		currency: currency,
+		amount:   amount,
+	}
+}
+
+func (m Money) Amount() int64      { return m.amount }
+func (m Money) Currency() Currency { return m.currency }
+
+// IsZero shows if Money amount equals to zero.
+func (m Money) IsZero() bool {
+	return m.amount == 0
+}
+
+// IsNegative shows if Money amount is less than zero.
+func (m Money) IsNegative() bool {
+	return m.amount < 0
+}
+
+// Add returns sum of m and other.
+// If currencies are different, it returns ErrCurrencyMismatch.
+func (m Money) Add(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return New(m.amount+other.amount, m.currency), nil
+}
+
+// Sub returns difference of m and other.
+// If currencies are different, it returns ErrCurrencyMismatch.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.currency != other.currency {
+		return Money{}, ErrCurrencyMismatch
+	}
+
+	return New(m.amount-other.amount, m.currency), nil
+}
+
+// Multiply returns m multiplied by n.
+func (m Money) Multiply(n int64) Money {
+	return New(m.amount*n, m.currency)
+}
+
+func (m Money) String() string {
+	return fmt.Sprintf("%d %s", m.amount, m.currency)
+}
@@ -0,0 +1,60 @@
+package money
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	testCases := []struct {
+		name    string
+		code    string
+		wantErr bool
+	}{
+		{name: "OK", code: "USD"},
+		{name: "lower case", code: "usd", wantErr: true},
+		{name: "too short", code: "US", wantErr: true},
+		{name: "empty", code: "", wantErr: true},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			currency, err := ParseCurrency(tc.code)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidCurrency)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.code, currency.String())
+			}
+		})
+	}
+}
+
+func TestMoney_Add(t *testing.T) {
+	t.Run("assert same currency adds", func(t *testing.T) {
+		sum, err := New(100, "USD").Add(New(250, "USD"))
+
+		assert.NoError(t, err)
+		assert.Equal(t, New(350, "USD"), sum)
+	})
+	t.Run("assert different currency returns error", func(t *testing.T) {
+		_, err := New(100, "USD").Add(New(250, "EUR"))
+
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
+
+func TestMoney_Sub(t *testing.T) {
+	t.Run("assert same currency subtracts", func(t *testing.T) {
+		diff, err := New(100, "USD").Sub(New(250, "USD"))
+
+		assert.NoError(t, err)
+		assert.True(t, diff.IsNegative())
+		assert.Equal(t, int64(-150), diff.Amount())
+	})
+	t.Run("assert different currency returns error", func(t *testing.T) {
+		_, err := New(100, "USD").Sub(New(250, "EUR"))
+
+		assert.ErrorIs(t, err, ErrCurrencyMismatch)
+	})
+}
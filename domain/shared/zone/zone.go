@@ -0,0 +1,110 @@
+// Package zone describes areas restaurants deliver orders to.
+package zone
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"service/domain/shared/destination"
+)
+
+var (
+	ErrZoneNotFound   = errors.New("delivery zone not found")
+	ErrInvalidPolygon = errors.New("invalid polygon: must have at least 3 vertices")
+	ErrInvalidRadius  = errors.New("invalid radius: must not be negative")
+	ErrEmptyZone      = errors.New("delivery zone must have either radius or polygons")
+)
+
+// Provider provides delivery zone of a restaurant.
+type Provider interface {
+	// Zone returns delivery Zone of the restaurant. If restaurant has no zone, [ErrZoneNotFound] is returned.
+	Zone(ctx context.Context, restaurantID uuid.UUID) (Zone, error)
+}
+
+// Polygon represents a closed area on the map. Last vertex is connected to the first one.
+// Polygon is a value object.
+type Polygon struct {
+	vertices []destination.Destination
+}
+
+// NewPolygon creates Polygon.
+func NewPolygon(vertices ...destination.Destination) (Polygon, error) {
+	if len(vertices) > 1 && vertices[0] == vertices[len(vertices)-1] {
+		// closed ring, e.g. from GeoJSON
+		vertices = vertices[:len(vertices)-1]
+	}
+
+	if len(vertices) < 3 {
+		return Polygon{}, ErrInvalidPolygon
+	}
+
+	return Polygon{vertices: vertices}, nil
+}
+
+// Contains shows if Polygon contains d. It uses ray casting that treats coordinates as planar,
+// which is precise enough for city-sized polygons.
+func (p Polygon) Contains(d destination.Destination) bool {
+	var (
+		inside bool
+		x, y   = d.Longitude(), d.Latitude()
+	)
+
+	for i, j := 0, len(p.vertices)-1; i < len(p.vertices); j, i = i, i+1 {
+		xi, yi := p.vertices[i].Longitude(), p.vertices[i].Latitude()
+		xj, yj := p.vertices[j].Longitude(), p.vertices[j].Latitude()
+
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+
+	return inside
+}
+
+// Zone represents an area restaurant delivers orders to:
+// a circle with radius around the restaurant, polygons, or both.
+// Zone is a value object.
+type Zone struct {
+	// center states for restaurant location.
+	center destination.Destination
+
+	// radius states for maximum distance from center in kilometers. Zero means no radius.
+	radius float64
+
+	polygons []Polygon
+}
+
+// NewZone creates Zone.
+func NewZone(center destination.Destination, radius float64, polygons ...Polygon) (Zone, error) {
+	if radius < 0 {
+		return Zone{}, ErrInvalidRadius
+	}
+
+	if radius == 0 && len(polygons) == 0 {
+		return Zone{}, ErrEmptyZone
+	}
+
+	return Zone{
+		center:   center,
+		radius:   radius,
+		polygons: polygons,
+	}, nil
+}
+
+func (z Zone) Center() destination.Destination { return z.center }
+func (z Zone) Radius() float64                 { return z.radius }
+
+// Contains shows if d is inside the Zone radius or any of its polygons.
+func (z Zone) Contains(d destination.Destination) bool {
+	if z.radius > 0 && z.center.DistanceTo(d) <= z.radius {
+		return true
+	}
+
+	for _, polygon := range z.polygons {
+		if polygon.Contains(d) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,70 @@
+package zone_test
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"service/domain/shared/zone"
+	"testing"
+)
+
+func point(t *testing.T, lat, long float64) destination.Destination {
+	d, err := destination.NewDestination(lat, long)
+	require.NoError(t, err)
+
+	return d
+}
+
+func TestZone_Contains(t *testing.T) {
+	square, err := zone.NewPolygon(
+		point(t, 50.0, 30.0),
+		point(t, 50.0, 30.1),
+		point(t, 50.1, 30.1),
+		point(t, 50.1, 30.0),
+		point(t, 50.0, 30.0),
+	)
+	require.NoError(t, err)
+
+	testCases := []struct { //nolint:govet
+		name     string
+		radius   float64
+		polygons []zone.Polygon
+		point    destination.Destination
+		contains bool
+	}{
+		{"inside radius", 5, nil, point(t, 50.2, 30.55), true},
+		{"outside radius", 5, nil, point(t, 50.3, 30.55), false},
+		{"inside polygon", 0, []zone.Polygon{square}, point(t, 50.05, 30.05), true},
+		{"outside polygon", 0, []zone.Polygon{square}, point(t, 50.05, 30.15), false},
+		{"outside polygon inside radius", 5, []zone.Polygon{square}, point(t, 50.2, 30.55), true},
+	}
+
+	center := point(t, 50.2, 30.5)
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			z, err := zone.NewZone(center, tc.radius, tc.polygons...)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.contains, z.Contains(tc.point))
+		})
+	}
+}
+
+func TestNewZone(t *testing.T) {
+	t.Run("assert zone must not be empty", func(t *testing.T) {
+		_, err := zone.NewZone(point(t, 0, 0), 0)
+		assert.ErrorIs(t, err, zone.ErrEmptyZone)
+	})
+
+	t.Run("assert radius must not be negative", func(t *testing.T) {
+		_, err := zone.NewZone(point(t, 0, 0), -1)
+		assert.ErrorIs(t, err, zone.ErrInvalidRadius)
+	})
+
+	t.Run("assert polygon must have at least 3 vertices", func(t *testing.T) {
+		_, err := zone.NewPolygon(point(t, 0, 0), point(t, 1, 1), point(t, 0, 0))
+		assert.ErrorIs(t, err, zone.ErrInvalidPolygon)
+	})
+}
@@ -86,8 +86,28 @@ func BadRequest(ctx context.Context, w http.ResponseWriter, err error) {
 	formatErrorResponse(ctx, w, err, http.StatusBadRequest)
 }
 
+func Forbidden(ctx context.Context, w http.ResponseWriter, err error) {
+	formatErrorResponse(ctx, w, err, http.StatusForbidden)
+}
+
+func NotFound(ctx context.Context, w http.ResponseWriter, err error) {
+	formatErrorResponse(ctx, w, err, http.StatusNotFound)
+}
+
+func Conflict(ctx context.Context, w http.ResponseWriter, err error) {
+	formatErrorResponse(ctx, w, err, http.StatusConflict)
+}
+
+func UnprocessableEntity(ctx context.Context, w http.ResponseWriter, err error) {
+	formatErrorResponse(ctx, w, err, http.StatusUnprocessableEntity)
+}
+
 /////////// 500 ///////////
 
 func InternalServerError(ctx context.Context, w http.ResponseWriter, err error) {
 	formatErrorResponse(ctx, w, err, http.StatusInternalServerError)
 }
+
+func ServiceUnavailable(ctx context.Context, w http.ResponseWriter, err error) {
+	formatErrorResponse(ctx, w, err, http.StatusServiceUnavailable)
+}
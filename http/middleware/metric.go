@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"bufio"
 	"fmt"
+	"net"
 	"net/http"
 	"service/metrics"
 	"strconv"
@@ -35,6 +37,16 @@ func (w *wrappedResponseWriter) WriteHeader(statusCode int) {
 	w.w.WriteHeader(statusCode)
 }
 
+// Unwrap lets [http.ResponseController] flush and hijack the underlying writer.
+func (w *wrappedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.w
+}
+
+// Hijack lets WebSocket connections be upgraded through the wrapper.
+func (w *wrappedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return http.NewResponseController(w.w).Hijack()
+}
+
 func RecordRequestHit(handlerName string) func(http.Handler) http.Handler {
 	metric := fmt.Sprintf("%s_request_hit_total", handlerName)
 
@@ -0,0 +1,122 @@
+// Package notify delivers notifications about changed orders to subscribers in the process.
+package notify
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"sync"
+)
+
+var ErrBrokerClosed = errors.New("notify: broker closed")
+
+// subscription receives notifications of one order. Notifications which are not received yet are coalesced.
+type subscription struct {
+	id uuid.UUID
+	c  chan struct{}
+}
+
+// Broker fans notifications out to subscribers of changed orders.
+type Broker struct {
+	mu            sync.Mutex
+	subscriptions map[uuid.UUID]map[*subscription]struct{}
+	closed        bool
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscriptions: make(map[uuid.UUID]map[*subscription]struct{})}
+}
+
+// Subscribe returns channel which receives a value when order with id has changed.
+// Channel is closed when ctx is done or broker is closed.
+func (b *Broker) Subscribe(ctx context.Context, id uuid.UUID) (<-chan struct{}, error) {
+	s := &subscription{id: id, c: make(chan struct{}, 1)}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBrokerClosed
+	}
+
+	if b.subscriptions[id] == nil {
+		b.subscriptions[id] = make(map[*subscription]struct{})
+	}
+
+	b.subscriptions[id][s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.unsubscribe(s)
+	}()
+
+	return s.c, nil
+}
+
+func (b *Broker) unsubscribe(s *subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subscriptions[s.id][s]; !ok {
+		// broker has been closed
+		return
+	}
+
+	delete(b.subscriptions[s.id], s)
+
+	if len(b.subscriptions[s.id]) == 0 {
+		delete(b.subscriptions, s.id)
+	}
+
+	close(s.c)
+}
+
+// Notify notifies subscribers of order with id.
+func (b *Broker) Notify(id uuid.UUID) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for s := range b.subscriptions[id] {
+		notify(s)
+	}
+}
+
+// NotifyAll notifies every subscriber, e.g. when notifications might have been lost.
+func (b *Broker) NotifyAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, subscriptions := range b.subscriptions {
+		for s := range subscriptions {
+			notify(s)
+		}
+	}
+}
+
+// Close closes channels of every subscriber. Broker can't be subscribed to after it is closed.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	b.closed = true
+
+	for _, subscriptions := range b.subscriptions {
+		for s := range subscriptions {
+			close(s.c)
+		}
+	}
+
+	b.subscriptions = nil
+}
+
+// notify sends notification unless subscriber has not received the previous one yet.
+func notify(s *subscription) {
+	select {
+	case s.c <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,99 @@
+package notify_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/infrastructure/notify"
+	"testing"
+	"time"
+)
+
+// received tells if c has received a value or has been closed.
+func received(c <-chan struct{}) (value, closed bool) {
+	select {
+	case _, ok := <-c:
+		return ok, !ok
+	case <-time.After(100 * time.Millisecond):
+		return false, false
+	}
+}
+
+func TestBroker(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("assert only subscribers of changed order are notified", func(t *testing.T) {
+		b := notify.NewBroker()
+		id := uuid.New()
+
+		first, err := b.Subscribe(ctx, id)
+		require.NoError(t, err)
+
+		second, err := b.Subscribe(ctx, id)
+		require.NoError(t, err)
+
+		other, err := b.Subscribe(ctx, uuid.New())
+		require.NoError(t, err)
+
+		b.Notify(id)
+
+		value, _ := received(first)
+		assert.True(t, value)
+
+		value, _ = received(second)
+		assert.True(t, value)
+
+		value, _ = received(other)
+		assert.False(t, value)
+	})
+
+	t.Run("assert notifications are coalesced", func(t *testing.T) {
+		b := notify.NewBroker()
+		id := uuid.New()
+
+		c, err := b.Subscribe(ctx, id)
+		require.NoError(t, err)
+
+		b.Notify(id)
+		b.NotifyAll()
+
+		value, _ := received(c)
+		assert.True(t, value)
+
+		value, _ = received(c)
+		assert.False(t, value)
+	})
+
+	t.Run("assert channel is closed when subscription is done", func(t *testing.T) {
+		b := notify.NewBroker()
+		id := uuid.New()
+
+		subscriptionCtx, cancel := context.WithCancel(ctx)
+
+		c, err := b.Subscribe(subscriptionCtx, id)
+		require.NoError(t, err)
+
+		cancel()
+
+		_, closed := received(c)
+		assert.True(t, closed)
+
+		b.Notify(id)
+	})
+
+	t.Run("assert channels are closed when broker is closed", func(t *testing.T) {
+		b := notify.NewBroker()
+
+		c, err := b.Subscribe(ctx, uuid.New())
+		require.NoError(t, err)
+
+		b.Close()
+
+		_, closed := received(c)
+		assert.True(t, closed)
+
+		_, err = b.Subscribe(ctx, uuid.New())
+		assert.ErrorIs(t, err, notify.ErrBrokerClosed)
+	})
+}
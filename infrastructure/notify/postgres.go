@@ -0,0 +1,103 @@
+package notify
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// Notifier is notified about changed orders.
+type Notifier interface {
+	Notify(id uuid.UUID)
+	NotifyAll()
+}
+
+// Listener listens to Postgres notifications which payload is id of changed order and passes them to Notifier.
+type Listener struct {
+	dsn      string
+	channel  string
+	notifier Notifier
+	logger   *zerolog.Logger
+
+	// retryInterval is how long Listener waits before reconnecting to the database.
+	retryInterval time.Duration
+}
+
+type ListenerOption func(*Listener)
+
+// WithRetryInterval sets how long Listener waits before reconnecting to the database.
+func WithRetryInterval(interval time.Duration) ListenerOption {
+	return func(l *Listener) {
+		l.retryInterval = interval
+	}
+}
+
+func NewListener(dsn, channel string, notifier Notifier, logger *zerolog.Logger, opts ...ListenerOption) *Listener {
+	l := &Listener{
+		dsn:           dsn,
+		channel:       channel,
+		notifier:      notifier,
+		logger:        logger,
+		retryInterval: 5 * time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
+}
+
+// Run listens to notifications until ctx is done. Lost connection is reestablished.
+func (l *Listener) Run(ctx context.Context) {
+	for {
+		err := l.listen(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+
+		l.logger.Error().Err(err).Str("channel", l.channel).Msg("order notifications listener failed")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(l.retryInterval):
+		}
+	}
+}
+
+func (l *Listener) listen(ctx context.Context) error {
+	conn, err := pgx.Connect(ctx, l.dsn)
+	if err != nil {
+		return errors.Wrap(err, "failed to connect to database")
+	}
+
+	defer func() {
+		_ = conn.Close(context.WithoutCancel(ctx))
+	}()
+
+	if _, err = conn.Exec(ctx, "LISTEN "+pgx.Identifier{l.channel}.Sanitize()); err != nil {
+		return errors.Wrapf(err, "failed to listen to %s", l.channel)
+	}
+
+	// orders might have changed while listener was not connected
+	l.notifier.NotifyAll()
+
+	for {
+		n, waitErr := conn.WaitForNotification(ctx)
+		if waitErr != nil {
+			return errors.Wrap(waitErr, "failed to wait for notification")
+		}
+
+		id, parseErr := uuid.Parse(n.Payload)
+		if parseErr != nil {
+			l.logger.Warn().Err(parseErr).Str("payload", n.Payload).Msg("invalid order notification")
+			continue
+		}
+
+		l.notifier.Notify(id)
+	}
+}
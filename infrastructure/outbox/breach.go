@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	orderevent "service/domain/order/event"
+	"service/event"
+	"service/pubsub"
+)
+
+// BreachWriter publishes SLA breaches to [pubsub.SLABreached].
+type BreachWriter struct {
+	marshaller event.Marshaler
+}
+
+func NewBreachWriter(marshaller event.Marshaler) *BreachWriter {
+	return &BreachWriter{
+		marshaller: marshaller,
+	}
+}
+
+// WriteBreach publishes breach with publisher.
+// Message UUID is the id of the transition order has entered breached state with,
+// so consumers can deduplicate redelivered breaches.
+func (w *BreachWriter) WriteBreach(ctx context.Context, publisher message.Publisher, b order.Breach) error {
+	bytes, err := w.marshaller.Marshal(&orderevent.JSONSLABreached{
+		OrderID:    b.OrderID,
+		State:      b.State.String(),
+		EnteredAt:  b.EnteredAt,
+		MaxDwell:   b.MaxDwell.String(),
+		Dwell:      b.Dwell().String(),
+		DetectedAt: b.DetectedAt,
+	})
+	if err != nil {
+		return errors.Wrap(err, "outbox: failed to marshal sla breached event")
+	}
+
+	msg := message.NewMessage(b.ChangeID.String(), bytes)
+	msg.SetContext(ctx)
+
+	if err = publisher.Publish(pubsub.SLABreached.String(), msg); err != nil {
+		return errors.Wrap(err, "outbox: failed to publish sla breached event")
+	}
+
+	return nil
+}
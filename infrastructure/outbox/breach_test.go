@@ -0,0 +1,55 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	orderevent "service/domain/order/event"
+	"service/event"
+	"service/infrastructure/outbox"
+	"service/pubsub"
+	"testing"
+	"time"
+)
+
+func TestBreachWriter_WriteBreach(t *testing.T) {
+	writer := outbox.NewBreachWriter(event.JSONMarshaler{})
+
+	entered := time.Now().Add(-time.Hour).UTC()
+	breach := order.Breach{
+		OrderID:    uuid.New(),
+		State:      order.Cooking,
+		ChangeID:   uuid.New(),
+		EnteredAt:  entered,
+		MaxDwell:   45 * time.Minute,
+		DetectedAt: entered.Add(time.Hour),
+	}
+
+	t.Run("assert breach is published", func(t *testing.T) {
+		publisher := &recordingPublisher{published: map[string][]*message.Message{}}
+
+		require.NoError(t, writer.WriteBreach(context.Background(), publisher, breach))
+
+		messages := publisher.published[pubsub.SLABreached.String()]
+		require.Len(t, messages, 1)
+		assert.Equal(t, breach.ChangeID.String(), messages[0].UUID)
+
+		e := &orderevent.JSONSLABreached{}
+		require.NoError(t, json.Unmarshal(messages[0].Payload, e))
+
+		assert.Equal(t, breach.OrderID, e.OrderID)
+		assert.Equal(t, order.Cooking.String(), e.State)
+		assert.Equal(t, "45m0s", e.MaxDwell)
+		assert.Equal(t, "1h0m0s", e.Dwell)
+	})
+
+	t.Run("assert publishing error is returned", func(t *testing.T) {
+		publisher := &recordingPublisher{err: assert.AnError}
+
+		assert.ErrorIs(t, writer.WriteBreach(context.Background(), publisher, breach), assert.AnError)
+	})
+}
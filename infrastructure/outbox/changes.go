@@ -0,0 +1,55 @@
+package outbox
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/event"
+	"service/pubsub"
+)
+
+// ChangesWriter is a Writer of order domain events,
+// so every saved state transition is published to [pubsub.StatusChanged].
+type ChangesWriter struct {
+	marshaller event.Marshaler
+}
+
+func NewChangesWriter(marshaller event.Marshaler) *ChangesWriter {
+	return &ChangesWriter{
+		marshaller: marshaller,
+	}
+}
+
+// Write publishes every state transition recorded in changes of o with publisher.
+// Message UUID is the transition id, so consumers can deduplicate redelivered transitions.
+func (w *ChangesWriter) Write(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+	var messages []*message.Message
+
+	for _, change := range o.Changes() {
+		e, ok := order.StatusChangedEvent(change)
+		if !ok {
+			continue
+		}
+
+		bytes, err := w.marshaller.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, "outbox: failed to marshal status changed event")
+		}
+
+		msg := message.NewMessage(e.ID.String(), bytes)
+		msg.SetContext(ctx)
+
+		messages = append(messages, msg)
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	if err := publisher.Publish(pubsub.StatusChanged.String(), messages...); err != nil {
+		return errors.Wrap(err, "outbox: failed to publish status changed events")
+	}
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	orderevent "service/domain/order/event"
+	"service/domain/order/ordertest"
+	"service/event"
+	"service/infrastructure/outbox"
+	"service/pubsub"
+	"testing"
+)
+
+// recordingPublisher keeps published messages by topic.
+type recordingPublisher struct {
+	published map[string][]*message.Message
+	err       error
+}
+
+func (p *recordingPublisher) Publish(topic string, messages ...*message.Message) error {
+	if p.err != nil {
+		return p.err
+	}
+
+	p.published[topic] = append(p.published[topic], messages...)
+
+	return nil
+}
+
+func (p *recordingPublisher) Close() error { return nil }
+
+func TestChangesWriter_Write(t *testing.T) {
+	writer := outbox.NewChangesWriter(event.JSONMarshaler{})
+
+	t.Run("assert every state transition is published", func(t *testing.T) {
+		o := ordertest.NewOrder(t)
+		publisher := &recordingPublisher{published: map[string][]*message.Message{}}
+
+		_, err := order.NewStateOperator(o, order.WithActor(order.ActorPayment)).PayOrder(uuid.New(), o.Total())
+		require.NoError(t, err)
+
+		require.NoError(t, writer.Write(context.Background(), publisher, o))
+
+		messages := publisher.published[pubsub.StatusChanged.String()]
+		require.Len(t, messages, 2)
+
+		paid := &orderevent.JSONStatusChanged{}
+		require.NoError(t, json.Unmarshal(messages[1].Payload, paid))
+
+		assert.Equal(t, paid.ID.String(), messages[1].UUID)
+		assert.Equal(t, o.ID(), paid.OrderID)
+		assert.Equal(t, order.Created.Name, paid.From)
+		assert.Equal(t, order.Paid.Name, paid.To)
+		assert.Equal(t, order.ActorPayment.String(), paid.Actor)
+	})
+
+	t.Run("assert publishing error is returned", func(t *testing.T) {
+		publisher := &recordingPublisher{err: assert.AnError}
+
+		err := writer.Write(context.Background(), publisher, ordertest.NewOrder(t))
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -6,59 +6,300 @@ import (
 	"github.com/go-feast/topics"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
+	"gorm.io/gorm"
 	"service/domain/order"
+	orderevent "service/domain/order/event"
 	"service/event"
+	"service/pubsub"
+	"time"
 )
 
+// Writer writes messages about order with publisher scoped to the transaction order is saved in,
+// so messages are published if and only if order is saved.
+type Writer interface {
+	Write(ctx context.Context, publisher message.Publisher, o *order.Order) error
+}
+
+// WriterFunc is a function Writer.
+type WriterFunc func(ctx context.Context, publisher message.Publisher, o *order.Order) error
+
+func (f WriterFunc) Write(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+	return f(ctx, publisher, o)
+}
+
+// TxWriter is a Writer which may also store data about order with tx order is saved in.
+// Repositories which save orders in the SQL database call WriteTx instead of Write.
+type TxWriter interface {
+	Writer
+
+	WriteTx(ctx context.Context, tx *gorm.DB, publisher message.Publisher, o *order.Order) error
+}
+
+var _ TxWriter = Writers(nil)
+
+// Writers writes with every not nil Writer in order.
+type Writers []Writer
+
+func (ws Writers) Write(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+	for _, w := range ws {
+		if w == nil {
+			continue
+		}
+
+		if err := w.Write(ctx, publisher, o); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ws Writers) WriteTx(ctx context.Context, tx *gorm.DB, publisher message.Publisher, o *order.Order) error {
+	for _, w := range ws {
+		if w == nil {
+			continue
+		}
+
+		if err := WriteTx(ctx, tx, publisher, o, w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// WriteTx writes with w. If w is TxWriter, it writes with tx as well.
+func WriteTx(ctx context.Context, tx *gorm.DB, publisher message.Publisher, o *order.Order, w Writer) error {
+	if txWriter, ok := w.(TxWriter); ok {
+		return txWriter.WriteTx(ctx, tx, publisher, o)
+	}
+
+	return w.Write(ctx, publisher, o)
+}
+
+// Repository stores orders along with outbox messages.
+type Repository interface {
+	order.Repository
+
+	// CreateWith creates order and writes messages with w in the same transaction.
+	CreateWith(ctx context.Context, o *order.Order, w Writer) error
+
+	// OperateWith operates order and writes messages with w in the same transaction. w is called after op.
+	// If op records no changes, nothing is stored and w is not called.
+	OperateWith(ctx context.Context, id uuid.UUID, op order.Operation, w Writer) error
+}
+
+// Outbox saves orders and writes events about them into the outbox in one transaction.
+// Events are relayed from the outbox to the broker by the consumer.
 type Outbox struct {
-	publisher  message.Publisher
 	marshaller event.Marshaler
-	repository order.Repository
+	repository Repository
 }
 
 func NewOutbox(
-	publisher message.Publisher,
-	repository order.Repository,
+	repository Repository,
 	marshaller event.Marshaler,
 ) *Outbox {
 	return &Outbox{
-		publisher:  publisher,
 		marshaller: marshaller,
 		repository: repository,
 	}
 }
 
+// Save creates order and writes [topics.OrderCreated] event.
+// Scheduled order is only created: the event is written when order is released, see [Outbox.Release].
 func (ob *Outbox) Save(
 	ctx context.Context,
 	o *order.Order,
-) (err error) {
-	var repositoryError, marshallError, publishError error
+) error {
+	return ob.SaveWith(ctx, o, nil)
+}
+
+// SaveWith is Save which also writes with w in the same transaction if w is not nil.
+func (ob *Outbox) SaveWith(
+	ctx context.Context,
+	o *order.Order,
+	w Writer,
+) error {
+	created := ob.writer(topics.OrderCreated.String(), func(o *order.Order) event.Event {
+		if o.Is(order.Scheduled) {
+			return nil
+		}
+
+		return o.ToEvent().JSONEventOrderCreated()
+	})
+
+	err := ob.repository.CreateWith(ctx, o, Writers{created, w})
+	if err != nil {
+		return errors.Wrap(err, "outbox: saving")
+	}
+
+	return nil
+}
+
+// Modify operates order with modify and writes [pubsub.Modified] event.
+// Order is not modified if the event cannot be written.
+func (ob *Outbox) Modify(
+	ctx context.Context,
+	id uuid.UUID,
+	modify func(*order.Order) error,
+) (*order.Order, error) {
+	var modified *order.Order
 
-	defer func() {
-		if marshallError != nil || publishError != nil {
-			err = ob.repository.Delete(ctx, o)
+	err := ob.repository.OperateWith(ctx, id, func(o *order.Order) error {
+		if err := modify(o); err != nil {
+			return err
 		}
-	}()
 
-	repositoryError = ob.repository.Create(ctx, o)
-	if repositoryError != nil {
-		return errors.Wrap(repositoryError, "outbox: saving: failed to create order")
+		modified = o
+
+		return nil
+	}, ob.writer(pubsub.Modified.String(), func(o *order.Order) event.Event {
+		return o.ToEvent().JSONEventOrderModified()
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "outbox: modifying")
 	}
 
-	bytes, marshallError := ob.marshaller.Marshal(
-		o.ToEvent().JSONEventOrderCreated())
-	if marshallError != nil {
-		return errors.Wrap(marshallError, "outbox: saving: failed to marshal event")
+	return modified, nil
+}
+
+// Release moves scheduled order into the normal flow and writes [topics.OrderCreated] event.
+// If order has been already released, nothing is written.
+func (ob *Outbox) Release(ctx context.Context, id uuid.UUID) error {
+	var released bool
+
+	err := ob.repository.OperateWith(ctx, id, func(o *order.Order) error {
+		released = false
+
+		if !o.Is(order.Scheduled) {
+			return nil
+		}
+
+		_, err := order.NewStateOperator(o,
+			order.WithActor(order.ActorSystem),
+		).ReleaseOrder()
+		if err != nil {
+			return err
+		}
+
+		released = true
+
+		return nil
+	}, ob.writer(topics.OrderCreated.String(), func(o *order.Order) event.Event {
+		if !released {
+			return nil
+		}
+
+		return o.ToEvent().JSONEventOrderCreated()
+	}))
+	if err != nil {
+		return errors.Wrap(err, "outbox: releasing")
+	}
+
+	return nil
+}
+
+// Cancel cancels order with reason on behalf of actor and writes [pubsub.Canceled] event.
+// It returns [order.ErrOrderCanceled] if order has been already canceled
+// and [order.ErrOrderClosed] if order has been closed.
+func (ob *Outbox) Cancel(ctx context.Context, id uuid.UUID, reason string, actor order.Actor) (*order.Order, error) {
+	var canceled *order.Order
+
+	err := ob.repository.OperateWith(ctx, id, func(o *order.Order) error {
+		if !o.Cancellation().IsZero() {
+			return errors.Wrapf(order.ErrOrderCanceled, "order %s", o.ID())
+		}
+
+		_, err := order.NewStateOperator(o,
+			order.WithActor(actor),
+		).CancelOrder(reason)
+		if err != nil {
+			return err
+		}
+
+		canceled = o
+
+		return nil
+	}, ob.writer(pubsub.Canceled.String(), func(o *order.Order) event.Event {
+		return canceledEvent(o)
+	}))
+	if err != nil {
+		return nil, errors.Wrap(err, "outbox: canceling")
 	}
 
-	msg := message.NewMessage(uuid.NewString(), bytes)
+	return canceled, nil
+}
+
+// CancelUnpaid cancels order which has been waiting for payment since before awaitingBefore
+// with [order.ReasonPaymentTimeout] and writes [pubsub.Canceled] event, see [order.Order.AwaitingPaymentSince].
+// Order which has been paid or started waiting for payment later is left untouched, so it is safe to cancel the same order concurrently:
+// only the first cancellation is stored, and the event is written once.
+func (ob *Outbox) CancelUnpaid(ctx context.Context, id uuid.UUID, awaitingBefore time.Time) error {
+	var canceled bool
+
+	err := ob.repository.OperateWith(ctx, id, func(o *order.Order) error {
+		canceled = false
+
+		if !o.Is(order.Created) || o.AwaitingPaymentSince().After(awaitingBefore) {
+			return nil
+		}
+
+		_, err := order.NewStateOperator(o,
+			order.WithActor(order.ActorSystem),
+		).CancelOrder(order.ReasonPaymentTimeout)
+		if err != nil {
+			return err
+		}
+
+		canceled = true
 
-	msg.SetContext(ctx)
+		return nil
+	}, ob.writer(pubsub.Canceled.String(), func(o *order.Order) event.Event {
+		if !canceled {
+			return nil
+		}
 
-	publishError = ob.publisher.Publish(topics.OrderCreated.String(), msg)
-	if publishError != nil {
-		return errors.Wrap(publishError, "outbox: saving: failed to publish event")
+		return canceledEvent(o)
+	}))
+	if err != nil {
+		return errors.Wrap(err, "outbox: canceling unpaid order")
 	}
 
 	return nil
 }
+
+// canceledEvent describes cancellation of order.
+func canceledEvent(o *order.Order) *orderevent.JSONCanceled {
+	return &orderevent.JSONCanceled{
+		OrderID:    o.ID(),
+		Reason:     o.Cancellation().Reason(),
+		CanceledBy: o.Cancellation().By().String(),
+		CanceledAt: o.Cancellation().At(),
+	}
+}
+
+// writer returns Writer that writes event made by toEvent about order to topic. If event is nil, nothing is written.
+func (ob *Outbox) writer(topic string, toEvent func(o *order.Order) event.Event) Writer {
+	return WriterFunc(func(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+		e := toEvent(o)
+		if e == nil {
+			return nil
+		}
+
+		bytes, err := ob.marshaller.Marshal(e)
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal event")
+		}
+
+		msg := message.NewMessage(uuid.NewString(), bytes)
+
+		msg.SetContext(ctx)
+
+		if err = publisher.Publish(topic, msg); err != nil {
+			return errors.Wrap(err, "failed to publish event")
+		}
+
+		return nil
+	})
+}
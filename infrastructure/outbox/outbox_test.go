@@ -0,0 +1,427 @@
+package outbox_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/go-feast/topics"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	orderevent "service/domain/order/event"
+	"service/domain/order/ordertest"
+	"service/event"
+	"service/infrastructure/outbox"
+	"service/infrastructure/repositories/order/eventsourced"
+	"service/pubsub"
+	"testing"
+	"time"
+)
+
+// faultyRepository replaces publisher of the outbox with failing one if publishErr is set
+// and records whether order has been deleted.
+type faultyRepository struct {
+	outbox.Repository
+
+	publishErr error
+	deleted    bool
+}
+
+func (r *faultyRepository) CreateWith(ctx context.Context, o *order.Order, w outbox.Writer) error {
+	return r.Repository.CreateWith(ctx, o, r.writer(w))
+}
+
+func (r *faultyRepository) OperateWith(ctx context.Context, id uuid.UUID, op order.Operation, w outbox.Writer) error {
+	return r.Repository.OperateWith(ctx, id, op, r.writer(w))
+}
+
+func (r *faultyRepository) Delete(ctx context.Context, o *order.Order) error {
+	r.deleted = true
+	return r.Repository.Delete(ctx, o)
+}
+
+func (r *faultyRepository) writer(w outbox.Writer) outbox.Writer {
+	return outbox.WriterFunc(func(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+		if r.publishErr != nil {
+			publisher = &recordingPublisher{err: r.publishErr}
+		}
+
+		return w.Write(ctx, publisher, o)
+	})
+}
+
+type failingMarshaler struct{}
+
+func (failingMarshaler) Marshal(event.Event) ([]byte, error) { return nil, assert.AnError }
+
+func newOutbox(t *testing.T, marshaller event.Marshaler) (*outbox.Outbox, *faultyRepository, *eventsourced.MemoryStore) {
+	t.Helper()
+
+	store := eventsourced.NewMemoryStore()
+	repository := &faultyRepository{Repository: eventsourced.NewRepository(store)}
+
+	return outbox.NewOutbox(repository, marshaller), repository, store
+}
+
+func TestOutbox_Save(t *testing.T) {
+	ctx := context.Background()
+	created := topics.OrderCreated.String()
+
+	t.Run("assert order and event are stored together", func(t *testing.T) {
+		ob, _, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+
+		require.NoError(t, ob.Save(ctx, o))
+
+		_, _, err := store.Load(ctx, o.ID())
+		require.NoError(t, err)
+		assert.Len(t, store.Messages(created), 1)
+	})
+
+	t.Run("assert event of scheduled order is not written", func(t *testing.T) {
+		ob, _, store := newOutbox(t, event.JSONMarshaler{})
+
+		o := ordertest.NewScheduledOrder(t)
+
+		require.NoError(t, ob.Save(ctx, o))
+
+		assert.Empty(t, store.Messages(created))
+	})
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		marshaller event.Marshaler
+		publishErr error
+	}{
+		{
+			name:       "marshalling fails",
+			marshaller: failingMarshaler{},
+		},
+		{
+			name:       "publishing fails",
+			marshaller: event.JSONMarshaler{},
+			publishErr: assert.AnError,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run("assert nothing is stored if "+tc.name, func(t *testing.T) {
+			ob, repository, store := newOutbox(t, tc.marshaller)
+			repository.publishErr = tc.publishErr
+			o := ordertest.NewOrder(t)
+
+			err := ob.Save(ctx, o)
+			assert.ErrorIs(t, err, assert.AnError)
+
+			_, _, err = store.Load(ctx, o.ID())
+			assert.ErrorIs(t, err, order.ErrOrderNotFound)
+			assert.Empty(t, store.Messages(created))
+			assert.False(t, repository.deleted, "nothing must be compensated")
+		})
+	}
+
+	t.Run("assert event is not written if order is not created", func(t *testing.T) {
+		ob, _, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+
+		require.NoError(t, ob.Save(ctx, o))
+
+		assert.Error(t, ob.Save(ctx, o), "order must not be created twice")
+		assert.Len(t, store.Messages(created), 1)
+	})
+}
+
+func TestOutbox_Modify(t *testing.T) {
+	ctx := context.Background()
+	modified := pubsub.Modified.String()
+
+	modify := func(o *order.Order) error {
+		_, err := order.NewStateOperator(o).ModifyItems([]order.ItemParams{
+			{MealID: uuid.NewString(), Quantity: 2, UnitPrice: 700},
+		})
+		return err
+	}
+
+	t.Run("assert modification and event are stored together", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		got, err := ob.Modify(ctx, o.ID(), modify)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(1400), got.Subtotal().Amount())
+		assert.Len(t, store.Messages(modified), 1)
+	})
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		marshaller event.Marshaler
+		publishErr error
+		modify     func(o *order.Order) error
+	}{
+		{
+			name:       "modification fails",
+			marshaller: event.JSONMarshaler{},
+			modify:     func(*order.Order) error { return assert.AnError },
+		},
+		{
+			name:       "marshalling fails",
+			marshaller: failingMarshaler{},
+			modify:     modify,
+		},
+		{
+			name:       "publishing fails",
+			marshaller: event.JSONMarshaler{},
+			publishErr: assert.AnError,
+			modify:     modify,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run("assert nothing is stored if "+tc.name, func(t *testing.T) {
+			ob, repository, store := newOutbox(t, tc.marshaller)
+			o := ordertest.NewOrder(t)
+			require.NoError(t, repository.Create(ctx, o))
+
+			repository.publishErr = tc.publishErr
+
+			_, err := ob.Modify(ctx, o.ID(), tc.modify)
+			assert.ErrorIs(t, err, assert.AnError)
+
+			got, err := repository.Get(ctx, o.ID())
+			require.NoError(t, err)
+
+			assert.Equal(t, o.Subtotal(), got.Subtotal())
+			assert.Empty(t, store.Messages(modified))
+		})
+	}
+}
+
+func TestOutbox_Release(t *testing.T) {
+	ctx := context.Background()
+	created := topics.OrderCreated.String()
+
+	t.Run("assert released order event is written once", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewScheduledOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		require.NoError(t, ob.Release(ctx, o.ID()))
+		require.NoError(t, ob.Release(ctx, o.ID()))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Created, got.State())
+		assert.Len(t, store.Messages(created), 1)
+	})
+
+	t.Run("assert order is not released if publishing fails", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewScheduledOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		repository.publishErr = assert.AnError
+
+		assert.ErrorIs(t, ob.Release(ctx, o.ID()), assert.AnError)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Scheduled, got.State())
+		assert.Empty(t, store.Messages(created))
+	})
+}
+
+func TestOutbox_CancelUnpaid(t *testing.T) {
+	ctx := context.Background()
+	canceled := pubsub.Canceled.String()
+
+	t.Run("assert unpaid order is canceled once", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), time.Now()))
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), time.Now()))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Canceled, got.State())
+		assert.Equal(t, order.ReasonPaymentTimeout, got.Cancellation().Reason())
+		assert.Equal(t, order.ActorSystem, got.Cancellation().By())
+
+		messages := store.Messages(canceled)
+		require.Len(t, messages, 1)
+
+		e := &orderevent.JSONCanceled{}
+		require.NoError(t, json.Unmarshal(messages[0].Payload, e))
+
+		assert.Equal(t, o.ID(), e.OrderID)
+		assert.Equal(t, order.ReasonPaymentTimeout, e.Reason)
+	})
+
+	t.Run("assert paid order is not canceled", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := order.NewStateOperator(o).PayOrder(uuid.New(), o.Total())
+			return err
+		}))
+
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), time.Now()))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Paid, got.State())
+		assert.Empty(t, store.Messages(canceled))
+	})
+
+	t.Run("assert order created after deadline is not canceled", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), o.CreateAt().Add(-time.Minute)))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Created, got.State())
+		assert.Empty(t, store.Messages(canceled))
+	})
+
+	t.Run("assert released scheduled order is canceled once it has been waiting for payment", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewScheduledOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		// deadline passes after order has been created, but before it has been released
+		deadline := time.Now()
+		time.Sleep(time.Millisecond)
+
+		require.NoError(t, ob.Release(ctx, o.ID()))
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), deadline))
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Created, got.State())
+		assert.Empty(t, store.Messages(canceled))
+
+		require.NoError(t, ob.CancelUnpaid(ctx, o.ID(), time.Now()))
+
+		got, err = repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Canceled, got.State())
+		assert.Len(t, store.Messages(canceled), 1)
+	})
+
+	t.Run("assert order is not canceled if publishing fails", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		repository.publishErr = assert.AnError
+
+		assert.ErrorIs(t, ob.CancelUnpaid(ctx, o.ID(), time.Now()), assert.AnError)
+
+		got, err := repository.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Created, got.State())
+		assert.Empty(t, store.Messages(canceled))
+	})
+}
+
+func TestOutbox_Cancel(t *testing.T) {
+	ctx := context.Background()
+	canceled := pubsub.Canceled.String()
+
+	t.Run("assert cancellation and event are stored together", func(t *testing.T) {
+		ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+		require.NoError(t, repository.Create(ctx, o))
+
+		got, err := ob.Cancel(ctx, o.ID(), "changed my mind", order.ActorSupport)
+		require.NoError(t, err)
+
+		assert.Equal(t, order.Canceled, got.State())
+		assert.Equal(t, order.ActorSupport, got.Cancellation().By())
+
+		messages := store.Messages(canceled)
+		require.Len(t, messages, 1)
+
+		e := &orderevent.JSONCanceled{}
+		require.NoError(t, json.Unmarshal(messages[0].Payload, e))
+
+		assert.Equal(t, "changed my mind", e.Reason)
+		assert.Equal(t, order.ActorSupport.String(), e.CanceledBy)
+	})
+
+	testCases := []struct { //nolint:govet
+		name string
+
+		prepare func(o *order.Order) error
+		actor   order.Actor
+
+		expectedErr error
+	}{
+		{
+			name: "already canceled",
+			prepare: func(o *order.Order) error {
+				_, err := order.NewStateOperator(o).CancelOrder("")
+				return err
+			},
+			actor:       order.ActorCustomer,
+			expectedErr: order.ErrOrderCanceled,
+		},
+		{
+			name: "closed",
+			prepare: func(o *order.Order) error {
+				_, err := order.NewStateOperator(o, order.WithActor(order.ActorSystem)).CloseOrder()
+				return err
+			},
+			actor:       order.ActorCustomer,
+			expectedErr: order.ErrOrderClosed,
+		},
+		{
+			name: "forbidden",
+			prepare: func(o *order.Order) error {
+				_, err := order.NewStateOperator(o).PayOrder(uuid.New(), o.Total())
+				return err
+			},
+			actor:       order.ActorCourier,
+			expectedErr: order.ErrCancellationForbidden,
+		},
+	}
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run("assert nothing is stored if order is "+tc.name, func(t *testing.T) {
+			ob, repository, store := newOutbox(t, event.JSONMarshaler{})
+			o := ordertest.NewOrder(t)
+			require.NoError(t, repository.Create(ctx, o))
+			require.NoError(t, repository.Operate(ctx, o.ID(), tc.prepare))
+
+			before, err := repository.Get(ctx, o.ID())
+			require.NoError(t, err)
+
+			_, err = ob.Cancel(ctx, o.ID(), "", tc.actor)
+			assert.ErrorIs(t, err, tc.expectedErr)
+
+			got, err := repository.Get(ctx, o.ID())
+			require.NoError(t, err)
+
+			assert.Equal(t, before.Version(), got.Version())
+			assert.Empty(t, store.Messages(canceled))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package outbox_test
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/go-feast/topics"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	"service/event"
+	"service/infrastructure/outbox"
+	repository "service/infrastructure/repositories/order/gorm"
+	"service/pubsub"
+	"testing"
+)
+
+// openDB connects to the test database and initializes orders scheme and topicNames, see [ordertest.OpenDB].
+func openDB(t *testing.T, topicNames ...string) *gorm.DB {
+	t.Helper()
+
+	db := ordertest.OpenDB(t)
+
+	order.InitializeOrderScheme(db)
+	require.NoError(t, pubsub.InitializeSQLTopics(db, topicNames...))
+
+	return db
+}
+
+// txFaultyRepository fails writing the outbox within the transaction of the order with the real SQL publisher:
+// either publishing goes to a topic without a table, so the insert itself fails, or writer fails after publishing.
+type txFaultyRepository struct {
+	outbox.Repository
+
+	// missingTopic is a topic messages are published to instead of the original one if set.
+	missingTopic string
+	writeErr     error
+}
+
+func (r *txFaultyRepository) CreateWith(ctx context.Context, o *order.Order, w outbox.Writer) error {
+	return r.Repository.CreateWith(ctx, o, outbox.WriterFunc(func(ctx context.Context, publisher message.Publisher, o *order.Order) error {
+		if r.missingTopic != "" {
+			publisher = &redirectingPublisher{Publisher: publisher, topic: r.missingTopic}
+		}
+
+		if err := w.Write(ctx, publisher, o); err != nil {
+			return err
+		}
+
+		return r.writeErr
+	}))
+}
+
+// redirectingPublisher publishes every message to topic.
+type redirectingPublisher struct {
+	message.Publisher
+	topic string
+}
+
+func (p *redirectingPublisher) Publish(_ string, messages ...*message.Message) error {
+	return p.Publisher.Publish(p.topic, messages...)
+}
+
+func TestOutbox_SavePostgres(t *testing.T) {
+	created := topics.OrderCreated.String()
+	db := openDB(t, created)
+	ctx := context.Background()
+
+	// published counts messages of the order stored in the topic table.
+	published := func(t *testing.T, o *order.Order) int64 {
+		t.Helper()
+
+		var count int64
+
+		err := db.Table(`"watermill_`+created+`"`).
+			Where("payload::text LIKE ?", "%"+o.ID().String()+"%").
+			Count(&count).Error
+		require.NoError(t, err)
+
+		return count
+	}
+
+	t.Run("assert order and event are committed together", func(t *testing.T) {
+		r := repository.NewOrderRepository(db)
+		ob := outbox.NewOutbox(&txFaultyRepository{Repository: r}, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+
+		require.NoError(t, ob.Save(ctx, o))
+
+		_, err := r.Get(ctx, o.ID())
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, published(t, o))
+	})
+
+	t.Run("assert order is not written if publishing fails", func(t *testing.T) {
+		r := repository.NewOrderRepository(db)
+		ob := outbox.NewOutbox(&txFaultyRepository{
+			Repository:   r,
+			missingTopic: "missing." + uuid.NewString(),
+		}, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+
+		require.Error(t, ob.Save(ctx, o))
+
+		_, err := r.Get(ctx, o.ID())
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+	})
+
+	t.Run("assert published event is rolled back with order", func(t *testing.T) {
+		r := repository.NewOrderRepository(db)
+		ob := outbox.NewOutbox(&txFaultyRepository{Repository: r, writeErr: assert.AnError}, event.JSONMarshaler{})
+		o := ordertest.NewOrder(t)
+
+		assert.ErrorIs(t, ob.Save(ctx, o), assert.AnError)
+
+		_, err := r.Get(ctx, o.ID())
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+		assert.Zero(t, published(t, o))
+	})
+}
@@ -0,0 +1,203 @@
+package gorm
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"service/domain/order"
+	"service/domain/shared/idempotency"
+	"service/infrastructure/outbox"
+	"time"
+)
+
+var (
+	_ idempotency.Store = (*KeyRepository)(nil)
+	_ outbox.TxWriter   = completeWriter{}
+)
+
+// reserveAttempts is how many times reservation is retried if reserved key is released concurrently.
+const reserveAttempts = 3
+
+// DefaultReservationLease is how long request may hold reserved key before it is taken over by a retry.
+const DefaultReservationLease = 30 * time.Second
+
+func InitializeIdempotencyScheme(db *gorm.DB) {
+	err := db.AutoMigrate(&IdempotencyKeyDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to migrate database"))
+	}
+}
+
+// IdempotencyKeyDTO represents a request made with an idempotency key by a customer.
+// Response is null until request is completed.
+type IdempotencyKeyDTO struct { //nolint:govet
+	CustomerID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Key         string    `gorm:"type:varchar(255);primaryKey"`
+	Fingerprint string    `gorm:"type:char(64)"`
+	Response    []byte    `gorm:"type:jsonb"`
+	CreatedAt   time.Time `gorm:"index"`
+	CompletedAt *time.Time
+}
+
+func (IdempotencyKeyDTO) TableName() string { return "idempotency_keys" }
+
+func (d IdempotencyKeyDTO) toRecord() idempotency.Record {
+	return idempotency.Record{
+		Key:         idempotency.Key{CustomerID: d.CustomerID, Value: d.Key},
+		Fingerprint: d.Fingerprint,
+		Response:    d.Response,
+		CreatedAt:   d.CreatedAt,
+	}
+}
+
+type Option func(r *KeyRepository)
+
+// WithReservationLease sets how long request may hold reserved key before it is taken over by a retry.
+func WithReservationLease(lease time.Duration) Option {
+	return func(r *KeyRepository) {
+		r.lease = lease
+	}
+}
+
+type KeyRepository struct {
+	db *gorm.DB
+
+	// lease is how long request may hold reserved key before it is taken over by a retry.
+	lease time.Duration
+}
+
+func NewKeyRepository(db *gorm.DB, opts ...Option) *KeyRepository {
+	r := &KeyRepository{db: db, lease: DefaultReservationLease}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+// Reserve inserts the key unless it exists. Concurrent requests with the same key are reserved only once.
+// Key which has been reserved longer than the lease and is not completed is taken over.
+func (r *KeyRepository) Reserve(ctx context.Context, key idempotency.Key, fingerprint string) (idempotency.Record, bool, error) {
+	for i := 0; i < reserveAttempts; i++ {
+		// postgres keeps microseconds, reservation must be found by its creation time
+		now := time.Now().Truncate(time.Microsecond)
+		dto := IdempotencyKeyDTO{CustomerID: key.CustomerID, Key: key.Value, Fingerprint: fingerprint, CreatedAt: now}
+
+		result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&dto)
+		if result.Error != nil {
+			return idempotency.Record{}, false, errors.Wrap(result.Error, "gorm repository: idempotency key reserve")
+		}
+
+		if result.RowsAffected != 0 {
+			return dto.toRecord(), true, nil
+		}
+
+		result = r.db.WithContext(ctx).
+			Model(&IdempotencyKeyDTO{}).
+			Where("customer_id = ? AND key = ? AND completed_at IS NULL AND created_at < ?", key.CustomerID, key.Value, now.Add(-r.lease)).
+			Updates(map[string]any{"fingerprint": fingerprint, "created_at": now})
+		if result.Error != nil {
+			return idempotency.Record{}, false, errors.Wrap(result.Error, "gorm repository: idempotency key reserve: failed to take over key")
+		}
+
+		if result.RowsAffected != 0 {
+			return dto.toRecord(), true, nil
+		}
+
+		var existing IdempotencyKeyDTO
+
+		result = r.db.WithContext(ctx).Find(&existing, "customer_id = ? AND key = ?", key.CustomerID, key.Value)
+		if result.Error != nil {
+			return idempotency.Record{}, false, errors.Wrap(result.Error, "gorm repository: idempotency key reserve: failed to find key")
+		}
+
+		if result.RowsAffected != 0 {
+			return existing.toRecord(), false, nil
+		}
+
+		// key has been released after conflicting insert, try to reserve it again
+	}
+
+	return idempotency.Record{}, false, errors.Errorf("gorm repository: idempotency key reserve: %s: attempts exceeded", key)
+}
+
+func (r *KeyRepository) Complete(ctx context.Context, reserved idempotency.Record, response []byte) error {
+	return complete(r.db.WithContext(ctx), reserved, response)
+}
+
+// complete stores response of the reserved request with db.
+// Reservation which has been taken over by another request is not completed.
+func complete(db *gorm.DB, reserved idempotency.Record, response []byte) error {
+	result := reservation(db.Model(&IdempotencyKeyDTO{}), reserved).
+		Updates(map[string]any{"response": response, "completed_at": time.Now()})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "gorm repository: idempotency key complete")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.Errorf("gorm repository: idempotency key complete: %s is not reserved", reserved.Key)
+	}
+
+	return nil
+}
+
+// Release deletes the reservation unless its request has been completed or reservation has been taken over.
+func (r *KeyRepository) Release(ctx context.Context, reserved idempotency.Record) error {
+	result := reservation(r.db.WithContext(ctx), reserved).Delete(&IdempotencyKeyDTO{})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "gorm repository: idempotency key release")
+	}
+
+	return nil
+}
+
+// reservation scopes db to the reservation of the request which has not been completed.
+func reservation(db *gorm.DB, reserved idempotency.Record) *gorm.DB {
+	return db.Where("customer_id = ? AND key = ? AND created_at = ? AND completed_at IS NULL",
+		reserved.Key.CustomerID, reserved.Key.Value, reserved.CreatedAt)
+}
+
+// DeleteCreatedBefore removes keys created before provided time, so they may be used again.
+// It returns the number of removed keys.
+func (r *KeyRepository) DeleteCreatedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&IdempotencyKeyDTO{}, "created_at < ?", before)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "gorm repository: failed to delete idempotency keys")
+	}
+
+	return result.RowsAffected, nil
+}
+
+// OrderSaver saves orders taken with idempotency keys.
+type OrderSaver struct {
+	outbox *outbox.Outbox
+}
+
+func NewOrderSaver(ob *outbox.Outbox) *OrderSaver {
+	return &OrderSaver{outbox: ob}
+}
+
+// SaveOnce saves order and stores response of the request reserved under key in the same transaction,
+// so key is completed if and only if order is saved.
+func (s *OrderSaver) SaveOnce(ctx context.Context, o *order.Order, reserved idempotency.Record, response []byte) error {
+	return s.outbox.SaveWith(ctx, o, completeWriter{reserved: reserved, response: response})
+}
+
+// completeWriter completes idempotency key with tx order is saved in.
+type completeWriter struct {
+	reserved idempotency.Record
+	response []byte
+}
+
+// Write fails: key can be completed only in the transaction of the database order is saved in.
+func (w completeWriter) Write(context.Context, message.Publisher, *order.Order) error {
+	return errors.Errorf("gorm repository: idempotency key complete: %s: order is not saved in the database", w.reserved.Key)
+}
+
+func (w completeWriter) WriteTx(ctx context.Context, tx *gorm.DB, _ message.Publisher, _ *order.Order) error {
+	return complete(tx.WithContext(ctx), w.reserved, w.response)
+}
@@ -0,0 +1,196 @@
+package gorm_test
+
+import (
+	"context"
+	"github.com/go-feast/topics"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	"service/domain/shared/idempotency"
+	"service/event"
+	"service/infrastructure/outbox"
+	repository "service/infrastructure/repositories/idempotency/gorm"
+	orderrepository "service/infrastructure/repositories/order/gorm"
+	"service/pubsub"
+	"testing"
+	"time"
+)
+
+// openDB connects to the database provided by TEST_POSTGRES_DSN. Test is skipped if it is not set.
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := ordertest.OpenDB(t)
+
+	repository.InitializeIdempotencyScheme(db)
+
+	return db
+}
+
+// newKey returns a key which has not been used yet.
+func newKey() idempotency.Key {
+	return idempotency.Key{CustomerID: uuid.New(), Value: uuid.NewString()}
+}
+
+func TestKeyRepository(t *testing.T) {
+	db := openDB(t)
+	r := repository.NewKeyRepository(db)
+	ctx := context.Background()
+
+	t.Run("assert replay gets completed response", func(t *testing.T) {
+		key := newKey()
+
+		reservation, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		record, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.False(t, reserved)
+		assert.ErrorIs(t, record.Check("fingerprint"), idempotency.ErrRequestInProgress)
+
+		require.NoError(t, r.Complete(ctx, reservation, []byte(`{"order_id":"1"}`)))
+
+		record, reserved, err = r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.False(t, reserved)
+		assert.NoError(t, record.Check("fingerprint"))
+		assert.JSONEq(t, `{"order_id":"1"}`, string(record.Response))
+
+		assert.ErrorIs(t, record.Check("another"), idempotency.ErrFingerprintMismatch)
+	})
+
+	t.Run("assert equal keys of different customers do not collide", func(t *testing.T) {
+		key, another := newKey(), newKey()
+		another.Value = key.Value
+
+		_, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		_, reserved, err = r.Reserve(ctx, another, "another")
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("assert released key is reserved again", func(t *testing.T) {
+		key := newKey()
+
+		reservation, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		require.NoError(t, r.Release(ctx, reservation))
+
+		_, reserved, err = r.Reserve(ctx, key, "another")
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+
+	t.Run("assert completed key is not released", func(t *testing.T) {
+		key := newKey()
+
+		reservation, _, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.NoError(t, r.Complete(ctx, reservation, []byte(`{}`)))
+		require.NoError(t, r.Release(ctx, reservation))
+
+		_, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		assert.False(t, reserved)
+	})
+
+	t.Run("assert expired reservation is taken over", func(t *testing.T) {
+		leased := repository.NewKeyRepository(db, repository.WithReservationLease(time.Millisecond))
+		key := newKey()
+
+		expired, reserved, err := leased.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		time.Sleep(10 * time.Millisecond)
+
+		taken, reserved, err := leased.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		require.NoError(t, leased.Release(ctx, expired))
+		assert.Error(t, leased.Complete(ctx, expired, []byte(`{}`)), "taken over reservation must not be completed")
+		require.NoError(t, leased.Complete(ctx, taken, []byte(`{}`)))
+
+		record, reserved, err := leased.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.True(t, record.Completed())
+	})
+
+	t.Run("assert reservation within lease is not taken over", func(t *testing.T) {
+		key := newKey()
+
+		_, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		record, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		assert.False(t, reserved)
+		assert.ErrorIs(t, record.Check("fingerprint"), idempotency.ErrRequestInProgress)
+	})
+
+	t.Run("assert deleted key is reserved again", func(t *testing.T) {
+		key := newKey()
+
+		_, _, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+
+		deleted, err := r.DeleteCreatedBefore(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		assert.Positive(t, deleted)
+
+		_, reserved, err := r.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		assert.True(t, reserved)
+	})
+}
+
+func TestOrderSaver_SaveOnce(t *testing.T) {
+	db := openDB(t)
+
+	order.InitializeOrderScheme(db)
+	require.NoError(t, pubsub.InitializeSQLTopics(db, topics.OrderCreated.String()))
+
+	orders := orderrepository.NewOrderRepository(db)
+	keys := repository.NewKeyRepository(db)
+	saver := repository.NewOrderSaver(outbox.NewOutbox(orders, event.JSONMarshaler{}))
+	ctx := context.Background()
+
+	t.Run("assert key is completed along with saved order", func(t *testing.T) {
+		key := newKey()
+		o := ordertest.NewOrder(t)
+
+		reservation, reserved, err := keys.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		require.True(t, reserved)
+
+		require.NoError(t, saver.SaveOnce(ctx, o, reservation, []byte(`{"order_id":"1"}`)))
+
+		_, err = orders.Get(ctx, o.ID())
+		require.NoError(t, err)
+
+		record, _, err := keys.Reserve(ctx, key, "fingerprint")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"order_id":"1"}`, string(record.Response))
+	})
+
+	t.Run("assert order is not saved if key is not reserved", func(t *testing.T) {
+		o := ordertest.NewOrder(t)
+
+		require.Error(t, saver.SaveOnce(ctx, o, idempotency.Record{Key: newKey(), CreatedAt: time.Now()}, []byte(`{}`)))
+
+		_, err := orders.Get(ctx, o.ID())
+		assert.ErrorIs(t, err, order.ErrOrderNotFound)
+	})
+}
@@ -0,0 +1,199 @@
+// Package eventsourced provides order repository which stores orders as streams of their domain events.
+package eventsourced
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/infrastructure/outbox"
+)
+
+const (
+	// DefaultSnapshotEvery is how many events are stored between order snapshots by default.
+	DefaultSnapshotEvery = 50
+
+	// DefaultOperateRetries is how many times Operate is retried on concurrent modification by default.
+	DefaultOperateRetries = 3
+)
+
+// Repository stores orders as streams of their domain events and rebuilds orders by replaying them.
+type Repository struct {
+	store Store
+
+	// snapshotEvery states for how many events are stored between order snapshots.
+	// Zero means snapshots are not taken.
+	snapshotEvery int64
+
+	// retries states for how many times Operate is retried if order has been concurrently modified.
+	retries int
+}
+
+// Option configures Repository.
+type Option func(r *Repository)
+
+// WithSnapshotEvery sets how many events are stored between order snapshots. Zero disables snapshots.
+func WithSnapshotEvery(events int) Option {
+	return func(r *Repository) {
+		if events >= 0 {
+			r.snapshotEvery = int64(events)
+		}
+	}
+}
+
+// WithOperateRetries sets how many times Operate is retried on [order.ErrConcurrentModification].
+func WithOperateRetries(retries int) Option {
+	return func(r *Repository) {
+		if retries >= 0 {
+			r.retries = retries
+		}
+	}
+}
+
+func NewRepository(store Store, opts ...Option) *Repository {
+	r := &Repository{
+		store:         store,
+		snapshotEvery: DefaultSnapshotEvery,
+		retries:       DefaultOperateRetries,
+	}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
+}
+
+func (r *Repository) Create(ctx context.Context, o *order.Order) error {
+	return r.CreateWith(ctx, o, nil)
+}
+
+// CreateWith creates order and writes messages with w into the outbox along with order events.
+func (r *Repository) CreateWith(ctx context.Context, o *order.Order, w outbox.Writer) error {
+	if o.Version() != 0 || len(o.Changes()) == 0 {
+		return errors.New("event sourced repository: order create: order has been already stored")
+	}
+
+	if err := r.append(ctx, o, nil, w); err != nil {
+		return errors.Wrap(err, "event sourced repository: failed to create order")
+	}
+
+	return nil
+}
+
+func (r *Repository) Get(ctx context.Context, id uuid.UUID) (*order.Order, error) {
+	snapshot, events, err := r.store.Load(ctx, id)
+	if err != nil {
+		return nil, errors.Wrapf(err, "event sourced repository: order get: %s", id)
+	}
+
+	o, err := order.Replay(snapshot, events)
+	if err != nil {
+		return nil, errors.Wrapf(err, "event sourced repository: order get: %s", id)
+	}
+
+	return o, nil
+}
+
+// Operate rebuilds order, applies op to it and appends recorded changes to order events.
+// If order has got new events since it was rebuilt, the operation is retried with a fresh order.
+func (r *Repository) Operate(ctx context.Context, id uuid.UUID, op order.Operation) error {
+	return r.operateWithRetries(ctx, id, op, nil, nil)
+}
+
+// OperateWith is Operate which writes messages with w into the outbox along with order events.
+// If op records no changes, nothing is stored and w is not called.
+func (r *Repository) OperateWith(ctx context.Context, id uuid.UUID, op order.Operation, w outbox.Writer) error {
+	return r.operateWithRetries(ctx, id, op, nil, w)
+}
+
+// OperateOnce is Operate which records the message identified by key in the inbox along with order changes.
+// If the message is already in the inbox, the changes are not stored and false is returned.
+func (r *Repository) OperateOnce(ctx context.Context, key order.MessageKey, id uuid.UUID, op order.Operation) (bool, error) {
+	err := r.operateWithRetries(ctx, id, op, &key, nil)
+	if errors.Is(err, ErrDuplicateMessage) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *Repository) Delete(ctx context.Context, o *order.Order) error {
+	if err := r.store.Delete(ctx, o.ID()); err != nil {
+		return errors.Wrap(err, "event sourced repository: failed to delete order")
+	}
+
+	return nil
+}
+
+func (r *Repository) operateWithRetries(
+	ctx context.Context,
+	id uuid.UUID,
+	op order.Operation,
+	key *order.MessageKey,
+	w outbox.Writer,
+) error {
+	var err error
+
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		err = r.operate(ctx, id, op, key, w)
+		if !errors.Is(err, order.ErrConcurrentModification) {
+			return err
+		}
+	}
+
+	return errors.Wrapf(err, "order operate: gave up after %d retries", r.retries)
+}
+
+func (r *Repository) operate(
+	ctx context.Context,
+	id uuid.UUID,
+	op order.Operation,
+	key *order.MessageKey,
+	w outbox.Writer,
+) error {
+	o, err := r.Get(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "order operate: failed to get order")
+	}
+
+	err = op(o)
+	if err != nil {
+		return errors.Wrap(err, "order operate: failed to operate order")
+	}
+
+	if len(o.Changes()) == 0 && key == nil {
+		return nil
+	}
+
+	if err = r.append(ctx, o, key, w); err != nil {
+		return errors.Wrap(err, "order operate: failed to store order events")
+	}
+
+	return nil
+}
+
+// append stores order changes, taking a snapshot every snapshotEvery events.
+func (r *Repository) append(ctx context.Context, o *order.Order, key *order.MessageKey, w outbox.Writer) error {
+	err := r.store.Append(ctx, o, AppendOptions{
+		Snapshot: r.snapshotDue(o.Version(), o.Version()+int64(len(o.Changes()))),
+		Message:  key,
+		Writer:   w,
+	})
+	if err != nil {
+		return err
+	}
+
+	o.CommitChanges()
+
+	return nil
+}
+
+// snapshotDue shows if a snapshot should be taken after order goes from one version to another.
+func (r *Repository) snapshotDue(from, to int64) bool {
+	return r.snapshotEvery > 0 && from/r.snapshotEvery != to/r.snapshotEvery
+}
@@ -0,0 +1,84 @@
+package eventsourced_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	"service/infrastructure/repositories/order/eventsourced"
+	"testing"
+)
+
+func TestRepository(t *testing.T) {
+	ordertest.RepositoryContract(t, func(t *testing.T) order.Repository {
+		return eventsourced.NewRepository(eventsourced.NewMemoryStore(),
+			eventsourced.WithSnapshotEvery(3),
+			eventsourced.WithOperateRetries(10),
+		)
+	})
+}
+
+func TestRepository_Snapshots(t *testing.T) {
+	ctx := context.Background()
+	store := eventsourced.NewMemoryStore()
+	repository := eventsourced.NewRepository(store, eventsourced.WithSnapshotEvery(2))
+
+	o := ordertest.NewOrder(t)
+	require.NoError(t, repository.Create(ctx, o))
+
+	steps := []func(*order.StateOperator) (bool, error){
+		func(s *order.StateOperator) (bool, error) { return s.PayOrder(uuid.New(), o.Total()) },
+		(*order.StateOperator).CookOrder,
+		(*order.StateOperator).OrderFinished,
+	}
+
+	for _, step := range steps {
+		require.NoError(t, repository.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := step(order.NewStateOperator(o))
+			return err
+		}))
+	}
+
+	// placed, paid (state and payment), cooking, finished
+	snapshot, events, err := store.Load(ctx, o.ID())
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+
+	assert.Equal(t, int64(4), snapshot.Version())
+	assert.Len(t, events, 1)
+
+	got, err := repository.Get(ctx, o.ID())
+	require.NoError(t, err)
+
+	assert.Equal(t, order.Finished, got.State())
+	assert.Equal(t, int64(5), got.Version())
+	assert.Len(t, got.History(), 4)
+}
+
+func TestRepository_OperateOnce(t *testing.T) {
+	ctx := context.Background()
+	repository := eventsourced.NewRepository(eventsourced.NewMemoryStore())
+
+	o := ordertest.NewOrder(t)
+	require.NoError(t, repository.Create(ctx, o))
+
+	key := order.MessageKey{MessageID: uuid.NewString(), Handler: "order.paid"}
+	pay := func(o *order.Order) error {
+		_, err := order.NewStateOperator(o).PayOrder(uuid.New(), o.Total())
+		return err
+	}
+
+	processed, err := repository.OperateOnce(ctx, key, o.ID(), pay)
+	require.NoError(t, err)
+	assert.True(t, processed)
+
+	processed, err = repository.OperateOnce(ctx, key, o.ID(), pay)
+	require.NoError(t, err)
+	assert.False(t, processed)
+
+	got, err := repository.Get(ctx, o.ID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), got.Version())
+}
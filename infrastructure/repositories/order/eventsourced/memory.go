@@ -0,0 +1,136 @@
+package eventsourced
+
+import (
+	"context"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"sync"
+)
+
+// MemoryStore is a Store that keeps events in memory. It is safe for concurrent use.
+// Messages written into the outbox are kept in memory as well, see [MemoryStore.Messages].
+type MemoryStore struct {
+	mu        sync.Mutex
+	events    map[uuid.UUID][]order.DomainEvent
+	snapshots map[uuid.UUID]*order.DatabaseOrderDTO
+	inbox     map[order.MessageKey]struct{}
+	outbox    map[string][]*message.Message
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		events:    make(map[uuid.UUID][]order.DomainEvent),
+		snapshots: make(map[uuid.UUID]*order.DatabaseOrderDTO),
+		inbox:     make(map[order.MessageKey]struct{}),
+		outbox:    make(map[string][]*message.Message),
+	}
+}
+
+func (s *MemoryStore) Load(_ context.Context, id uuid.UUID) (*order.Order, []order.DomainEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events, ok := s.events[id]
+	if !ok {
+		return nil, nil, order.ErrOrderNotFound
+	}
+
+	var (
+		snapshot *order.Order
+		after    int64
+	)
+
+	if dto, ok := s.snapshots[id]; ok {
+		snapshot, after = dto.ToOrder(), dto.Version
+	}
+
+	stored := make([]order.DomainEvent, len(events)-int(after))
+	copy(stored, events[after:])
+
+	return snapshot, stored, nil
+}
+
+func (s *MemoryStore) Append(ctx context.Context, o *order.Order, opts AppendOptions) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if stored := int64(len(s.events[o.ID()])); stored != o.Version() {
+		return errors.Wrapf(order.ErrConcurrentModification, "order %s: version %d, stored %d", o.ID(), o.Version(), stored)
+	}
+
+	if opts.Message != nil {
+		if _, ok := s.inbox[*opts.Message]; ok {
+			return errors.Wrapf(ErrDuplicateMessage, "message %s handled by %s", opts.Message.MessageID, opts.Message.Handler)
+		}
+	}
+
+	changes := o.Changes()
+
+	// messages are buffered, so nothing is stored if Writer fails
+	publisher := &bufferedPublisher{messages: make(map[string][]*message.Message)}
+
+	if opts.Writer != nil && len(changes) != 0 {
+		if err := opts.Writer.Write(ctx, publisher, o); err != nil {
+			return errors.Wrap(err, "memory store: failed to write outbox messages")
+		}
+	}
+
+	if opts.Message != nil {
+		s.inbox[*opts.Message] = struct{}{}
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	s.events[o.ID()] = append(s.events[o.ID()], changes...)
+
+	for topic, messages := range publisher.messages {
+		s.outbox[topic] = append(s.outbox[topic], messages...)
+	}
+
+	if opts.Snapshot {
+		snapshot := o.ToDatabaseDTO()
+		snapshot.Version = o.Version() + int64(len(changes))
+		s.snapshots[o.ID()] = snapshot
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Delete(_ context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.events, id)
+	delete(s.snapshots, id)
+
+	return nil
+}
+
+// Messages returns messages written into the outbox to topic in order they were written.
+func (s *MemoryStore) Messages(topic string) []*message.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	messages := make([]*message.Message, len(s.outbox[topic]))
+	copy(messages, s.outbox[topic])
+
+	return messages
+}
+
+// bufferedPublisher keeps published messages until they are stored along with order events.
+type bufferedPublisher struct {
+	messages map[string][]*message.Message
+}
+
+func (p *bufferedPublisher) Publish(topic string, messages ...*message.Message) error {
+	p.messages[topic] = append(p.messages[topic], messages...)
+	return nil
+}
+
+func (p *bufferedPublisher) Close() error {
+	return nil
+}
@@ -0,0 +1,40 @@
+package eventsourced
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"service/domain/order"
+	"service/infrastructure/outbox"
+)
+
+// ErrDuplicateMessage is returned by Store if the message has been already recorded in the inbox.
+var ErrDuplicateMessage = errors.New("message has been already processed")
+
+// Store keeps events of orders.
+type Store interface {
+	// Load returns the latest snapshot of order, nil if there is none, and every event stored after the snapshot.
+	// It returns [order.ErrOrderNotFound] if order has no events.
+	Load(ctx context.Context, id uuid.UUID) (*order.Order, []order.DomainEvent, error)
+
+	// Append stores changes of order after its version in one transaction.
+	// It returns [order.ErrConcurrentModification] if order already has events after its version.
+	Append(ctx context.Context, o *order.Order, opts AppendOptions) error
+
+	// Delete removes every event and snapshot of order.
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// AppendOptions configures what is stored along with order changes.
+type AppendOptions struct {
+	// Snapshot states for storing order as a snapshot along with its changes.
+	Snapshot bool
+
+	// Message is recorded in the inbox along with order changes if it is not nil.
+	// If the message has been already recorded, Store returns [ErrDuplicateMessage].
+	Message *order.MessageKey
+
+	// Writer writes messages into the outbox along with order changes if it is not nil.
+	// If Writer fails, nothing is stored.
+	Writer outbox.Writer
+}
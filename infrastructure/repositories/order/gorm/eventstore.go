@@ -0,0 +1,235 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"service/domain/order"
+	"service/infrastructure/outbox"
+	"service/infrastructure/repositories/order/eventsourced"
+	"time"
+)
+
+var _ eventsourced.Store = (*EventStore)(nil)
+
+func InitializeEventStoreScheme(db *gorm.DB) {
+	err := db.AutoMigrate(&EventDTO{}, &SnapshotDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to migrate database"))
+	}
+}
+
+// EventDTO represents a single order domain event. Version is the position of the event in order`s stream.
+type EventDTO struct { //nolint:govet
+	OrderID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Version    int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name       string    `gorm:"type:text"`
+	Payload    []byte    `gorm:"type:jsonb"`
+	RecordedAt time.Time
+}
+
+func (EventDTO) TableName() string { return "order_events" }
+
+// SnapshotDTO represents the latest order snapshot. Version is the version of the last event in the snapshot.
+type SnapshotDTO struct { //nolint:govet
+	OrderID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Version int64
+	Payload []byte `gorm:"type:jsonb"`
+	TakenAt time.Time
+}
+
+func (SnapshotDTO) TableName() string { return "order_snapshots" }
+
+// EventStore keeps order events in the database.
+// Orders table is kept as a projection of the events, so orders can still be queried by their state.
+type EventStore struct {
+	db *gorm.DB
+
+	// changes writes stored events into the outbox. Events are not written if it is nil.
+	changes outbox.Writer
+}
+
+// EventStoreOption configures EventStore.
+type EventStoreOption func(s *EventStore)
+
+// WithEventsWriter sets Writer which writes order events into the outbox
+// in the same transaction they are stored in.
+func WithEventsWriter(w outbox.Writer) EventStoreOption {
+	return func(s *EventStore) {
+		s.changes = w
+	}
+}
+
+func NewEventStore(db *gorm.DB, opts ...EventStoreOption) *EventStore {
+	s := &EventStore{db: db}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+func (s *EventStore) Load(ctx context.Context, id uuid.UUID) (*order.Order, []order.DomainEvent, error) {
+	db := s.db.WithContext(ctx)
+
+	var (
+		snapshot    *order.Order
+		snapshotDTO SnapshotDTO
+	)
+
+	result := db.Limit(1).Find(&snapshotDTO, "order_id = ?", id)
+	if result.Error != nil {
+		return nil, nil, errors.Wrap(result.Error, "event store: failed to find snapshot")
+	}
+
+	if result.RowsAffected != 0 {
+		dto := &order.DatabaseOrderDTO{}
+		if err := json.Unmarshal(snapshotDTO.Payload, dto); err != nil {
+			return nil, nil, errors.Wrap(err, "event store: failed to unmarshal snapshot")
+		}
+
+		dto.Version = snapshotDTO.Version
+		snapshot = dto.ToOrder()
+	}
+
+	var stored []EventDTO
+
+	result = db.Order("version").Find(&stored, "order_id = ? AND version > ?", id, snapshotDTO.Version)
+	if result.Error != nil {
+		return nil, nil, errors.Wrap(result.Error, "event store: failed to find events")
+	}
+
+	if snapshot == nil && len(stored) == 0 {
+		return nil, nil, order.ErrOrderNotFound
+	}
+
+	events := make([]order.DomainEvent, len(stored))
+
+	for i, e := range stored {
+		event, err := order.UnmarshalDomainEvent(e.Name, e.Payload)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "event store: order %s: version %d", id, e.Version)
+		}
+
+		events[i] = event
+	}
+
+	return snapshot, events, nil
+}
+
+// Append stores order changes and updates order projection in one transaction.
+func (s *EventStore) Append(ctx context.Context, o *order.Order, opts eventsourced.AppendOptions) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		if opts.Message != nil {
+			err := receive(tx, *opts.Message)
+			if errors.Is(err, errDuplicateMessage) {
+				return errors.Wrap(eventsourced.ErrDuplicateMessage, err.Error())
+			}
+
+			if err != nil {
+				return errors.Wrap(err, "event store")
+			}
+		}
+
+		changes := o.Changes()
+		if len(changes) == 0 {
+			return nil
+		}
+
+		version := o.Version() + int64(len(changes))
+
+		if err := project(tx, o, version); err != nil {
+			return errors.Wrap(err, "event store: failed to project order")
+		}
+
+		events := make([]EventDTO, len(changes))
+
+		for i, change := range changes {
+			payload, err := json.Marshal(change)
+			if err != nil {
+				return errors.Wrapf(err, "event store: failed to marshal event %s", change.EventName())
+			}
+
+			events[i] = EventDTO{
+				OrderID:    o.ID(),
+				Version:    o.Version() + int64(i) + 1,
+				Name:       change.EventName(),
+				Payload:    payload,
+				RecordedAt: time.Now(),
+			}
+		}
+
+		if result := tx.Create(&events); result.Error != nil {
+			return errors.Wrap(result.Error, "event store: failed to store events")
+		}
+
+		if err := writeOutbox(ctx, tx, o, s.changes, opts.Writer); err != nil {
+			return errors.Wrap(err, "event store")
+		}
+
+		if !opts.Snapshot {
+			return nil
+		}
+
+		dto := o.ToDatabaseDTO()
+		dto.Version = version
+
+		payload, err := json.Marshal(dto)
+		if err != nil {
+			return errors.Wrap(err, "event store: failed to marshal snapshot")
+		}
+
+		result := tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&SnapshotDTO{
+			OrderID: o.ID(),
+			Version: version,
+			Payload: payload,
+			TakenAt: time.Now(),
+		})
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "event store: failed to store snapshot")
+		}
+
+		return nil
+	})
+}
+
+// Delete deletes order events, snapshots and projection in one transaction.
+func (s *EventStore) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		for _, model := range []any{&EventDTO{}, &SnapshotDTO{}} {
+			if result := tx.Delete(model, "order_id = ?", id); result.Error != nil {
+				return errors.Wrap(result.Error, "event store: failed to delete order events")
+			}
+		}
+
+		if err := deleteOrder(tx, id); err != nil {
+			return errors.Wrap(err, "event store: failed to delete order projection")
+		}
+
+		return nil
+	})
+}
+
+// project writes order in the orders table. A new order is created, a stored one is compared and swapped by version.
+func project(tx *gorm.DB, o *order.Order, version int64) error {
+	if o.Version() != 0 {
+		return save(tx, o, version)
+	}
+
+	dto := o.ToDatabaseDTO()
+	dto.Version = version
+
+	if result := tx.Create(dto); result.Error != nil {
+		return errors.Wrap(result.Error, "failed to create order")
+	}
+
+	return nil
+}
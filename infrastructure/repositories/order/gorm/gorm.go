@@ -2,55 +2,185 @@ package gorm
 
 import (
 	"context"
+	"fmt"
+	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"service/domain/order"
+	"service/infrastructure/outbox"
+	"service/pubsub"
+	"time"
 )
 
+// DefaultOperateRetries is how many times Operate is retried on concurrent modification by default.
+const DefaultOperateRetries = 3
+
 type OrderRepository struct {
 	db *gorm.DB
+
+	// retries states for how many times Operate is retried if order has been concurrently modified.
+	retries int
+
+	// changes writes order changes into the outbox. Changes are not written if it is nil.
+	changes outbox.Writer
+}
+
+// Option configures OrderRepository.
+type Option func(r *OrderRepository)
+
+// WithOperateRetries sets how many times Operate is retried on [order.ErrConcurrentModification].
+// Zero retries means Operate fails on the first conflict.
+func WithOperateRetries(retries int) Option {
+	return func(r *OrderRepository) {
+		if retries >= 0 {
+			r.retries = retries
+		}
+	}
 }
 
+// Delete deletes order along with its items, history and courier assignments in one transaction.
 func (r *OrderRepository) Delete(ctx context.Context, o *order.Order) error {
-	result := r.db.WithContext(ctx).Delete(o.ToDatabaseDTO())
-	if result.Error != nil {
-		return errors.Wrap(result.Error, "gorm repository: failed to delete order")
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := deleteOrder(tx.WithContext(ctx), o.ID()); err != nil {
+			return errors.Wrap(err, "gorm repository")
+		}
+
+		return nil
+	})
+}
+
+// deleteOrder deletes order along with its items, history and courier assignments with tx.
+func deleteOrder(tx *gorm.DB, id uuid.UUID) error {
+	for _, model := range []any{&order.ItemDTO{}, &order.StateChangeDTO{}, &order.CourierAssignmentDTO{}} {
+		if result := tx.Delete(model, "order_id = ?", id); result.Error != nil {
+			return errors.Wrap(result.Error, "failed to delete order associations")
+		}
+	}
+
+	if result := tx.Delete(&order.DatabaseOrderDTO{ID: id}); result.Error != nil {
+		return errors.Wrap(result.Error, "failed to delete order")
 	}
 
 	return nil
 }
 
+// WithChangesWriter sets Writer which writes order changes into the outbox
+// in the same transaction order is created or operated in.
+func WithChangesWriter(w outbox.Writer) Option {
+	return func(r *OrderRepository) {
+		r.changes = w
+	}
+}
+
 func NewOrderRepository(
 	db *gorm.DB,
+	opts ...Option,
 ) *OrderRepository {
-	return &OrderRepository{db}
+	r := &OrderRepository{db: db, retries: DefaultOperateRetries}
+
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	return r
 }
 
 func (r *OrderRepository) Create(ctx context.Context, o *order.Order) error {
-	result := r.db.WithContext(ctx).Create(o.ToDatabaseDTO())
-	if result.Error != nil {
-		return errors.Wrap(result.Error, "gorm repository: failed to create order")
-	}
+	return r.CreateWith(ctx, o, nil)
+}
 
-	return nil
+// CreateWith creates order and writes messages with w into the outbox in the same transaction.
+func (r *OrderRepository) CreateWith(ctx context.Context, o *order.Order, w outbox.Writer) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+
+		result := tx.Create(o.ToDatabaseDTO())
+		if result.Error != nil {
+			return errors.Wrap(result.Error, "gorm repository: failed to create order")
+		}
+
+		return r.write(ctx, tx, o, w)
+	})
 }
 
 func (r *OrderRepository) Get(ctx context.Context, id uuid.UUID) (*order.Order, error) {
 	o := &order.DatabaseOrderDTO{}
 
-	result := r.db.WithContext(ctx).Find(o, "id = ?", id)
+	result := r.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Preload("History", func(db *gorm.DB) *gorm.DB {
+			return db.Order("at")
+		}).
+		Preload("Assignments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("assigned_at")
+		}).
+		Find(o, "id = ?", id)
 	if result.Error != nil {
 		return nil, errors.Wrap(result.Error, "gorm repository: order get: failed to find order")
 	}
 
+	if result.RowsAffected == 0 {
+		return nil, errors.Wrapf(order.ErrOrderNotFound, "gorm repository: order get: %s", id)
+	}
+
 	return o.ToOrder(), nil
 }
 
+// Operate gets order, applies op to it and saves it in one transaction.
+// Order is saved only if nobody has saved it since it was read, otherwise the whole
+// transaction is retried with a fresh order. If every retry fails, [order.ErrConcurrentModification] is returned.
 func (r *OrderRepository) Operate(ctx context.Context, id uuid.UUID, op order.Operation) error {
+	return r.operateWithRetries(ctx, id, op, nil, nil)
+}
+
+// OperateWith is Operate which writes messages with w into the outbox in the same transaction after op is applied.
+// If op records no changes, nothing is stored and w is not called.
+func (r *OrderRepository) OperateWith(ctx context.Context, id uuid.UUID, op order.Operation, w outbox.Writer) error {
+	return r.operateWithRetries(ctx, id, op, nil, w)
+}
+
+func (r *OrderRepository) operateWithRetries(
+	ctx context.Context,
+	id uuid.UUID,
+	op order.Operation,
+	key *order.MessageKey,
+	w outbox.Writer,
+) error {
+	var err error
+
+	for attempt := 0; attempt <= r.retries; attempt++ {
+		err = r.operate(ctx, id, op, key, w)
+		if !errors.Is(err, order.ErrConcurrentModification) {
+			return err
+		}
+	}
+
+	return errors.Wrapf(err, "order operate: gave up after %d retries", r.retries)
+}
+
+// operate applies op to order in one transaction. If key is not nil, the message is recorded in the inbox first.
+// Order which op has recorded no changes of is neither saved nor written, so its version is left as is
+// and concurrent operations do not conflict with it.
+func (r *OrderRepository) operate(
+	ctx context.Context,
+	id uuid.UUID,
+	op order.Operation,
+	key *order.MessageKey,
+	w outbox.Writer,
+) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
 		tx = tx.WithContext(ctx)
-		// select order to escape data race
+
+		if key != nil {
+			if err := receive(tx, *key); err != nil {
+				return errors.Wrap(err, "order operate")
+			}
+		}
+
 		o, err := withTx(tx).Get(ctx, id)
 		if err != nil {
 			return errors.Wrap(err, "order operate: failed to get order")
@@ -61,15 +191,163 @@ func (r *OrderRepository) Operate(ctx context.Context, id uuid.UUID, op order.Op
 			return errors.Wrap(err, "order operate: failed to operate order")
 		}
 
-		result := tx.Save(o.ToDatabaseDTO())
-		if result.Error != nil {
-			return errors.Wrap(result.Error, "order operate: failed to save order")
+		if len(o.Changes()) == 0 {
+			return nil
 		}
 
-		return nil
+		if err = save(tx, o, o.Version()+1); err != nil {
+			return err
+		}
+
+		return r.write(ctx, tx, o, w)
 	})
 }
 
+// write writes order changes and messages of w into the outbox within tx.
+func (r *OrderRepository) write(ctx context.Context, tx *gorm.DB, o *order.Order, w outbox.Writer) error {
+	if err := writeOutbox(ctx, tx, o, r.changes, w); err != nil {
+		return errors.Wrap(err, "gorm repository")
+	}
+
+	return nil
+}
+
+// writeOutbox writes messages of every not nil Writer with publisher scoped to tx,
+// so messages are stored if and only if tx is committed. [outbox.TxWriter] writes with tx as well.
+func writeOutbox(ctx context.Context, tx *gorm.DB, o *order.Order, writers ...outbox.Writer) error {
+	var publisher message.Publisher
+
+	for _, w := range writers {
+		if w == nil {
+			continue
+		}
+
+		if publisher == nil {
+			var err error
+
+			publisher, err = pubsub.NewSQLTxPublisher(tx, nil)
+			if err != nil {
+				return errors.Wrap(err, "failed to create outbox publisher")
+			}
+		}
+
+		if err := outbox.WriteTx(ctx, tx, publisher, o, w); err != nil {
+			return errors.Wrap(err, "failed to write outbox messages")
+		}
+	}
+
+	return nil
+}
+
+// save saves order and sets its version. Of its associations only rows added or modified by order changes are written,
+// so stored history is not rewritten. Order is saved only if its version has not changed since it was read,
+// see [order.ErrConcurrentModification].
+func save(tx *gorm.DB, o *order.Order, version int64) error {
+	result := tx.Model(&order.DatabaseOrderDTO{}).
+		Where("id = ? AND version = ?", o.ID(), o.Version()).
+		Update("version", version)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "order save: failed to update order version")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(order.ErrConcurrentModification, "order save: %s: version %d", o.ID(), o.Version())
+	}
+
+	dto := o.ToDatabaseDTO()
+	dto.Version = version
+
+	result = tx.Omit(clause.Associations).Save(dto)
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "order save: failed to save order")
+	}
+
+	for _, change := range o.Changes() {
+		if err := saveChange(tx, o.ID(), change); err != nil {
+			return errors.Wrapf(err, "order save: %s", change.EventName())
+		}
+	}
+
+	return nil
+}
+
+// saveChange writes rows of order associations change has added or modified.
+func saveChange(tx *gorm.DB, id uuid.UUID, change order.DomainEvent) error {
+	var result *gorm.DB
+
+	switch e := change.(type) {
+	case order.StateChanged:
+		result = tx.Create(&e.Change)
+	case order.CourierAssigned:
+		result = tx.Create(&e.Assignment)
+	case order.CourierUnassigned:
+		result = tx.Model(&order.CourierAssignmentDTO{}).
+			Where("order_id = ? AND released_at IS NULL", id).
+			Updates(map[string]any{"released_at": e.At, "reason": e.Reason})
+	case order.ItemsModified:
+		if result = tx.Delete(&order.ItemDTO{}, "order_id = ?", id); result.Error == nil && len(e.Items) != 0 {
+			result = tx.Create(&e.Items)
+		}
+	default:
+		return nil
+	}
+
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to save order change")
+	}
+
+	return nil
+}
+
 func withTx(tx *gorm.DB) *OrderRepository {
 	return &OrderRepository{db: tx}
 }
+
+func (r *OrderRepository) FindDueScheduled(ctx context.Context, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+
+	result := r.db.WithContext(ctx).
+		Model(&order.DatabaseOrderDTO{}).
+		Where("state = ? AND release_at <= ?", order.Scheduled, before).
+		Order("release_at").
+		Limit(limit).
+		Pluck("id", &ids)
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "gorm repository: failed to find scheduled orders")
+	}
+
+	return ids, nil
+}
+
+// unpaidOrdersQuery selects orders which have been waiting for payment since before provided time.
+// Order waits for payment since it has entered [order.Created] state, scheduled order enters it on release.
+// Tables of orders and state changes are formatted in, see [tableNames].
+const unpaidOrdersQuery = `
+SELECT o.id
+FROM %[1]s o
+WHERE o.state = ?
+  AND COALESCE(
+    (SELECT max(c.at) FROM %[2]s c WHERE c.order_id = o.id AND c."to" = o.state),
+    o.release_at,
+    o.created_at
+  ) <= ?
+ORDER BY o.created_at
+LIMIT ?`
+
+func (r *OrderRepository) FindUnpaid(ctx context.Context, awaitingBefore time.Time, limit int) ([]uuid.UUID, error) {
+	tables, err := tableNames(r.db, &order.DatabaseOrderDTO{}, &order.StateChangeDTO{})
+	if err != nil {
+		return nil, errors.Wrap(err, "gorm repository")
+	}
+
+	var ids []uuid.UUID
+
+	result := r.db.WithContext(ctx).
+		Raw(fmt.Sprintf(unpaidOrdersQuery, tables...), order.Created, awaitingBefore, limit).
+		Scan(&ids)
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "gorm repository: failed to find unpaid orders")
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,189 @@
+package gorm_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	"service/infrastructure/repositories/order/eventsourced"
+	repository "service/infrastructure/repositories/order/gorm"
+	"testing"
+	"time"
+)
+
+// openDB connects to the database provided by TEST_POSTGRES_DSN. Test is skipped if it is not set.
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db := ordertest.OpenDB(t)
+
+	order.InitializeOrderScheme(db)
+	repository.InitializeInboxScheme(db)
+	repository.InitializeEventStoreScheme(db)
+
+	return db
+}
+
+func TestOrderRepository(t *testing.T) {
+	db := openDB(t)
+
+	ordertest.RepositoryContract(t, func(t *testing.T) order.Repository {
+		return repository.NewOrderRepository(db, repository.WithOperateRetries(10))
+	})
+}
+
+func TestEventStore(t *testing.T) {
+	db := openDB(t)
+
+	ordertest.RepositoryContract(t, func(t *testing.T) order.Repository {
+		return eventsourced.NewRepository(repository.NewEventStore(db),
+			eventsourced.WithSnapshotEvery(3),
+			eventsourced.WithOperateRetries(10),
+		)
+	})
+}
+
+func TestOrderRepository_Delete(t *testing.T) {
+	db := openDB(t)
+	ctx := context.Background()
+
+	repositories := map[string]order.Repository{
+		"state":  repository.NewOrderRepository(db),
+		"events": eventsourced.NewRepository(repository.NewEventStore(db)),
+	}
+
+	for name, r := range repositories {
+		t.Run("assert associations of deleted order are deleted: "+name, func(t *testing.T) {
+			o := ordertest.NewOrder(t)
+			operator := order.NewStateOperator(o)
+
+			_, err := operator.PayOrder(uuid.New(), o.Total())
+			require.NoError(t, err)
+
+			for _, step := range []func() (bool, error){operator.CookOrder, operator.OrderFinished, operator.WaitForCourier} {
+				_, err = step()
+				require.NoError(t, err)
+			}
+
+			_, err = operator.CourierTookOrder(uuid.New())
+			require.NoError(t, err)
+
+			require.NoError(t, r.Create(ctx, o))
+			require.NoError(t, r.Delete(ctx, o))
+
+			for _, model := range []any{&order.ItemDTO{}, &order.StateChangeDTO{}, &order.CourierAssignmentDTO{}} {
+				var count int64
+
+				require.NoError(t, db.Model(model).Where("order_id = ?", o.ID()).Count(&count).Error)
+				assert.Zero(t, count)
+			}
+		})
+	}
+}
+
+func TestOrderRepository_List(t *testing.T) {
+	db := openDB(t)
+	repository.InitializeQueryIndexes(db)
+
+	ctx := context.Background()
+	r := repository.NewOrderRepository(db)
+	customerID := uuid.New()
+
+	created := make([]*order.Order, 5)
+
+	for i := range created {
+		o := ordertest.NewCustomerOrder(t, customerID.String())
+		require.NoError(t, r.Create(ctx, o))
+
+		created[i] = o
+	}
+
+	t.Run("assert every order is listed once page by page", func(t *testing.T) {
+		q := order.ListQuery{
+			Filter: order.Filter{CustomerID: customerID, States: []order.State{order.Created}},
+			Sort:   order.SortCreatedAtAsc,
+			Limit:  2,
+		}
+
+		var listed []uuid.UUID
+
+		for {
+			page, err := r.List(ctx, q)
+			require.NoError(t, err)
+
+			for _, o := range page.Orders {
+				listed = append(listed, o.ID())
+			}
+
+			if page.Next == nil {
+				break
+			}
+
+			q.After = page.Next
+		}
+
+		expected := make([]uuid.UUID, len(created))
+		for i, o := range created {
+			expected[i] = o.ID()
+		}
+
+		assert.ElementsMatch(t, expected, listed)
+	})
+
+	t.Run("assert orders in other states are not listed", func(t *testing.T) {
+		page, err := r.List(ctx, order.ListQuery{
+			Filter: order.Filter{CustomerID: customerID, States: []order.State{order.Paid}},
+			Sort:   order.SortCreatedAtDesc,
+			Limit:  order.DefaultListLimit,
+		})
+		require.NoError(t, err)
+
+		assert.Empty(t, page.Orders)
+		assert.Nil(t, page.Next)
+	})
+}
+
+func TestOrderRepository_FindUnpaid(t *testing.T) {
+	db := openDB(t)
+
+	ctx := context.Background()
+	r := repository.NewOrderRepository(db)
+
+	newOrder := func(t *testing.T, opts ...order.Option) *order.Order {
+		o := ordertest.NewOrder(t, opts...)
+		require.NoError(t, r.Create(ctx, o))
+
+		return o
+	}
+
+	created := newOrder(t)
+	scheduled := newOrder(t, order.WithDeliveryTime(time.Now().Add(2*time.Hour)))
+
+	// deadline passes after orders have been created, but before scheduled one has been released
+	deadline := time.Now()
+	time.Sleep(time.Millisecond)
+
+	require.NoError(t, r.Operate(ctx, scheduled.ID(), func(o *order.Order) error {
+		_, err := order.NewStateOperator(o).ReleaseOrder()
+		return err
+	}))
+
+	t.Run("assert released scheduled order is not found before it has been waiting for payment", func(t *testing.T) {
+		ids, err := r.FindUnpaid(ctx, deadline, 1000)
+		require.NoError(t, err)
+
+		assert.Contains(t, ids, created.ID())
+		assert.NotContains(t, ids, scheduled.ID())
+	})
+
+	t.Run("assert released scheduled order is found once it has been waiting for payment", func(t *testing.T) {
+		ids, err := r.FindUnpaid(ctx, time.Now(), 1000)
+		require.NoError(t, err)
+
+		assert.Contains(t, ids, created.ID())
+		assert.Contains(t, ids, scheduled.ID())
+	})
+}
@@ -0,0 +1,80 @@
+package gorm
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"service/domain/order"
+	"time"
+)
+
+// errDuplicateMessage is returned from operation transaction if the message has been already processed.
+var errDuplicateMessage = errors.New("message has been already processed")
+
+func InitializeInboxScheme(db *gorm.DB) {
+	err := db.AutoMigrate(&InboxMessageDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to migrate database"))
+	}
+}
+
+// InboxMessageDTO records a message processed by a handler.
+type InboxMessageDTO struct { //nolint:govet
+	MessageID   string    `gorm:"type:text;primaryKey"`
+	Handler     string    `gorm:"type:text;primaryKey"`
+	ProcessedAt time.Time `gorm:"index"`
+}
+
+func (InboxMessageDTO) TableName() string { return "order_inbox" }
+
+// OperateOnce is Operate which records the message identified by key in the inbox in the same transaction.
+// If the message is already in the inbox, op is not applied and false is returned.
+func (r *OrderRepository) OperateOnce(
+	ctx context.Context,
+	key order.MessageKey,
+	id uuid.UUID,
+	op order.Operation,
+) (bool, error) {
+	err := r.operateWithRetries(ctx, id, op, &key, nil)
+	if errors.Is(err, errDuplicateMessage) {
+		return false, nil
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteProcessedBefore removes messages processed before provided time from the inbox.
+// It returns the number of removed messages.
+func (r *OrderRepository) DeleteProcessedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Delete(&InboxMessageDTO{}, "processed_at < ?", before)
+	if result.Error != nil {
+		return 0, errors.Wrap(result.Error, "gorm repository: failed to delete processed messages")
+	}
+
+	return result.RowsAffected, nil
+}
+
+// receive records the message in the inbox. Concurrent transaction receiving the same message
+// waits for this one and gets [errDuplicateMessage] if this one is committed.
+func receive(tx *gorm.DB, key order.MessageKey) error {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&InboxMessageDTO{
+		MessageID:   key.MessageID,
+		Handler:     key.Handler,
+		ProcessedAt: time.Now(),
+	})
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "failed to record message in inbox")
+	}
+
+	if result.RowsAffected == 0 {
+		return errors.Wrapf(errDuplicateMessage, "message %s handled by %s", key.MessageID, key.Handler)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package gorm
+
+import (
+	"fmt"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"service/domain/order"
+)
+
+// StateChangesChannel is the Postgres notification channel id of order is sent to when order changes its state.
+const StateChangesChannel = "order_state_changes"
+
+const notifyStateChangeFunction = `
+CREATE OR REPLACE FUNCTION notify_order_state_change() RETURNS trigger AS $$
+BEGIN
+  PERFORM pg_notify('%s', NEW.order_id::text);
+  RETURN NULL;
+END;
+$$ LANGUAGE plpgsql`
+
+// InitializeStateChangeNotifications makes every stored order state change notify [StateChangesChannel].
+// Notification is sent once the change is committed. Event order store projects state changes as well,
+// so it works with both order stores.
+func InitializeStateChangeNotifications(db *gorm.DB) {
+	changes, err := tableName(db, &order.StateChangeDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create state change notifications"))
+	}
+
+	statements := []string{
+		fmt.Sprintf(notifyStateChangeFunction, StateChangesChannel),
+		fmt.Sprintf("DROP TRIGGER IF EXISTS notify_order_state_change ON %s", changes),
+		fmt.Sprintf("CREATE TRIGGER notify_order_state_change AFTER INSERT ON %s "+
+			"FOR EACH ROW EXECUTE FUNCTION notify_order_state_change()", changes),
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if result := tx.Exec(statement); result.Error != nil {
+				return result.Error
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create state change notifications"))
+	}
+}
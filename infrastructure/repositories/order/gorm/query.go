@@ -0,0 +1,120 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"service/domain/order"
+)
+
+var _ order.Querier = (*OrderRepository)(nil)
+
+// listIndexes are indexes of orders table used by List.
+// Every filtered column is followed by keyset columns, so a page is read straight from an index.
+var listIndexes = map[string]string{
+	"idx_orders_created_id":            "created_at, id",
+	"idx_orders_customer_created_id":   "customer_id, created_at, id",
+	"idx_orders_restaurant_created_id": "restaurant_id, created_at, id",
+	"idx_orders_courier_created_id":    "courier_id, created_at, id",
+	"idx_orders_state_created_id":      "state, created_at, id",
+}
+
+// InitializeQueryIndexes creates indexes of orders table used by List.
+func InitializeQueryIndexes(db *gorm.DB) {
+	orders, err := tableName(db, &order.DatabaseOrderDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create orders indexes"))
+	}
+
+	for name, columns := range listIndexes {
+		err = db.Exec(fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", name, orders, columns)).Error
+		if err != nil {
+			panic(errors.Wrapf(err, "failed to create index %s", name))
+		}
+	}
+}
+
+// List returns a page of orders matching q using keyset pagination by created_at and id.
+func (r *OrderRepository) List(ctx context.Context, q order.ListQuery) (order.Page, error) {
+	if err := q.Validate(); err != nil {
+		return order.Page{}, errors.Wrap(err, "gorm repository: order list")
+	}
+
+	direction, compare := "DESC", "<"
+	if q.Sort == order.SortCreatedAtAsc {
+		direction, compare = "ASC", ">"
+	}
+
+	tx := filter(r.db.WithContext(ctx), q.Filter)
+
+	if q.After != nil {
+		tx = tx.Where(fmt.Sprintf("(created_at, id) %s (?, ?)", compare), q.After.CreatedAt, q.After.ID)
+	}
+
+	var dtos []*order.DatabaseOrderDTO
+
+	// one more order shows if there is the next page
+	result := tx.
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Preload("History", func(db *gorm.DB) *gorm.DB {
+			return db.Order("at")
+		}).
+		Preload("Assignments", func(db *gorm.DB) *gorm.DB {
+			return db.Order("assigned_at")
+		}).
+		Order(fmt.Sprintf("created_at %[1]s, id %[1]s", direction)).
+		Limit(q.Limit + 1).
+		Find(&dtos)
+	if result.Error != nil {
+		return order.Page{}, errors.Wrap(result.Error, "gorm repository: order list: failed to find orders")
+	}
+
+	var page order.Page
+
+	if len(dtos) > q.Limit {
+		dtos = dtos[:q.Limit]
+
+		last := dtos[len(dtos)-1]
+		page.Next = &order.Cursor{Sort: q.Sort, CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+
+	page.Orders = make([]*order.Order, len(dtos))
+	for i, dto := range dtos {
+		page.Orders[i] = dto.ToOrder()
+	}
+
+	return page, nil
+}
+
+// filter restricts tx to orders matching f.
+func filter(tx *gorm.DB, f order.Filter) *gorm.DB {
+	if f.CustomerID != uuid.Nil {
+		tx = tx.Where("customer_id = ?", f.CustomerID)
+	}
+
+	if f.RestaurantID != uuid.Nil {
+		tx = tx.Where("restaurant_id = ?", f.RestaurantID)
+	}
+
+	if f.CourierID != uuid.Nil {
+		tx = tx.Where("courier_id = ?", f.CourierID)
+	}
+
+	if len(f.States) != 0 {
+		tx = tx.Where("state IN ?", f.States)
+	}
+
+	if !f.CreatedFrom.IsZero() {
+		tx = tx.Where("created_at >= ?", f.CreatedFrom)
+	}
+
+	if !f.CreatedTo.IsZero() {
+		tx = tx.Where("created_at < ?", f.CreatedTo)
+	}
+
+	return tx
+}
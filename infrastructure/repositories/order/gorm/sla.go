@@ -0,0 +1,217 @@
+package gorm
+
+import (
+	"context"
+	"fmt"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"service/domain/order"
+	"service/pubsub"
+	"time"
+)
+
+var (
+	_ order.BreachRecorder = (*SLARepository)(nil)
+	_ order.BreachFinder   = (*SLARepository)(nil)
+)
+
+func InitializeSLAScheme(db *gorm.DB) {
+	err := db.AutoMigrate(&SLABreachDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to migrate database"))
+	}
+}
+
+// SLABreachDTO represents a recorded SLA breach.
+// ChangeID is the transition order has entered breached state with, so every stay in state is recorded once.
+type SLABreachDTO struct { //nolint:govet
+	ChangeID   uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	OrderID    uuid.UUID   `gorm:"type:uuid;index"`
+	State      order.State `gorm:"type:text"`
+	EnteredAt  time.Time
+	MaxDwell   time.Duration
+	DetectedAt time.Time
+}
+
+func (SLABreachDTO) TableName() string { return "order_sla_breaches" }
+
+func (d SLABreachDTO) toBreach() order.Breach {
+	return order.Breach{
+		OrderID:    d.OrderID,
+		State:      d.State,
+		ChangeID:   d.ChangeID,
+		EnteredAt:  d.EnteredAt,
+		MaxDwell:   d.MaxDwell,
+		DetectedAt: d.DetectedAt,
+	}
+}
+
+// BreachWriter writes SLA breach into the outbox within the transaction breach is recorded in.
+type BreachWriter interface {
+	WriteBreach(ctx context.Context, publisher message.Publisher, b order.Breach) error
+}
+
+// SLARepository records orders breaching SLA.
+// Orders table is queried directly, so it works with both state and event order stores.
+type SLARepository struct {
+	db *gorm.DB
+
+	// writer writes recorded breaches into the outbox. Breaches are not written if it is nil.
+	writer BreachWriter
+}
+
+func NewSLARepository(db *gorm.DB, writer BreachWriter) *SLARepository {
+	return &SLARepository{db: db, writer: writer}
+}
+
+// stuckOrdersQuery selects orders which have entered state before provided time and have not changed it since.
+// Orders which breach has been already recorded are skipped.
+// Tables of orders, state changes and breaches are formatted in, see [tableNames].
+const stuckOrdersQuery = `
+SELECT o.id AS order_id, o.state AS state, c.id AS change_id, c.at AS entered_at
+FROM %[1]s o
+JOIN %[2]s c ON c.order_id = o.id AND c."to" = o.state
+WHERE o.state = ? AND c.at <= ?
+  AND NOT EXISTS (SELECT 1 FROM %[2]s l WHERE l.order_id = o.id AND l.at > c.at)
+  AND NOT EXISTS (SELECT 1 FROM %[3]s b WHERE b.change_id = c.id)
+ORDER BY c.at
+LIMIT ?`
+
+// RecordBreaches records breaches of orders stuck in state and writes every recorded breach into the outbox
+// in one transaction. Breach being recorded concurrently is recorded and written only by one caller.
+func (r *SLARepository) RecordBreaches(
+	ctx context.Context,
+	state order.State,
+	maxDwell time.Duration,
+	limit int,
+) ([]order.Breach, error) {
+	tables, err := tableNames(r.db, &order.DatabaseOrderDTO{}, &order.StateChangeDTO{}, &SLABreachDTO{})
+	if err != nil {
+		return nil, errors.Wrap(err, "sla repository")
+	}
+
+	now := time.Now()
+
+	var stuck []SLABreachDTO
+
+	result := r.db.WithContext(ctx).
+		Raw(fmt.Sprintf(stuckOrdersQuery, tables...), state, now.Add(-maxDwell), limit).
+		Scan(&stuck)
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "sla repository: failed to find stuck orders")
+	}
+
+	if len(stuck) == 0 {
+		return nil, nil
+	}
+
+	var breaches []order.Breach
+
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.WithContext(ctx)
+		breaches = breaches[:0]
+
+		var publisher message.Publisher
+
+		for _, dto := range stuck {
+			dto.MaxDwell, dto.DetectedAt = maxDwell, now
+
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&dto)
+			if result.Error != nil {
+				return errors.Wrap(result.Error, "failed to record breach")
+			}
+
+			if result.RowsAffected == 0 {
+				continue
+			}
+
+			breach := dto.toBreach()
+			breaches = append(breaches, breach)
+
+			if r.writer == nil {
+				continue
+			}
+
+			if publisher == nil {
+				if publisher, err = pubsub.NewSQLTxPublisher(tx, nil); err != nil {
+					return errors.Wrap(err, "failed to create outbox publisher")
+				}
+			}
+
+			if err = r.writer.WriteBreach(ctx, publisher, breach); err != nil {
+				return errors.Wrap(err, "failed to write breach")
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "sla repository")
+	}
+
+	return breaches, nil
+}
+
+// openBreachesQuery selects breaches of orders which have not left breached state yet.
+// Tables of orders, state changes and breaches are formatted in, see [tableNames].
+const openBreachesQuery = `
+SELECT b.*
+FROM %[3]s b
+JOIN %[1]s o ON o.id = b.order_id AND o.state = b.state
+WHERE NOT EXISTS (
+  SELECT 1 FROM %[2]s l WHERE l.order_id = b.order_id AND l.at > b.entered_at
+)
+ORDER BY b.entered_at
+LIMIT ?`
+
+func (r *SLARepository) FindOpenBreaches(ctx context.Context, limit int) ([]order.Breach, error) {
+	tables, err := tableNames(r.db, &order.DatabaseOrderDTO{}, &order.StateChangeDTO{}, &SLABreachDTO{})
+	if err != nil {
+		return nil, errors.Wrap(err, "sla repository")
+	}
+
+	var dtos []SLABreachDTO
+
+	result := r.db.WithContext(ctx).
+		Raw(fmt.Sprintf(openBreachesQuery, tables...), limit).
+		Scan(&dtos)
+	if result.Error != nil {
+		return nil, errors.Wrap(result.Error, "sla repository: failed to find open breaches")
+	}
+
+	breaches := make([]order.Breach, len(dtos))
+	for i, dto := range dtos {
+		breaches[i] = dto.toBreach()
+	}
+
+	return breaches, nil
+}
+
+// tableName returns name of the table model is stored in.
+func tableName(db *gorm.DB, model any) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", errors.Wrap(err, "failed to parse model")
+	}
+
+	return stmt.Schema.Table, nil
+}
+
+// tableNames returns names of the tables models are stored in, in the order of models.
+func tableNames(db *gorm.DB, models ...any) ([]any, error) {
+	names := make([]any, len(models))
+
+	for i, model := range models {
+		name, err := tableName(db, model)
+		if err != nil {
+			return nil, err
+		}
+
+		names[i] = name
+	}
+
+	return names, nil
+}
@@ -0,0 +1,75 @@
+package gorm_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/order"
+	"service/domain/order/ordertest"
+	repository "service/infrastructure/repositories/order/gorm"
+	"testing"
+	"time"
+)
+
+func TestSLARepository(t *testing.T) {
+	db := openDB(t)
+	repository.InitializeSLAScheme(db)
+
+	ctx := context.Background()
+	orders := repository.NewOrderRepository(db)
+	sla := repository.NewSLARepository(db, nil)
+
+	o := ordertest.NewOrder(t)
+	require.NoError(t, orders.Create(ctx, o))
+
+	t.Run("assert breach is recorded once", func(t *testing.T) {
+		breaches, err := sla.RecordBreaches(ctx, order.Created, time.Nanosecond, 1000)
+		require.NoError(t, err)
+
+		var recorded *order.Breach
+
+		for i := range breaches {
+			if breaches[i].OrderID == o.ID() {
+				recorded = &breaches[i]
+			}
+		}
+
+		require.NotNil(t, recorded)
+		assert.Equal(t, order.Created, recorded.State)
+		assert.Equal(t, o.History()[0].ID(), recorded.ChangeID)
+
+		breaches, err = sla.RecordBreaches(ctx, order.Created, time.Nanosecond, 1000)
+		require.NoError(t, err)
+
+		for _, breach := range breaches {
+			assert.NotEqual(t, o.ID(), breach.OrderID)
+		}
+	})
+
+	t.Run("assert breach is closed when order changes state", func(t *testing.T) {
+		assert.True(t, containsBreach(t, sla, o))
+
+		require.NoError(t, orders.Operate(ctx, o.ID(), func(o *order.Order) error {
+			_, err := order.NewStateOperator(o).PayOrder(uuid.New(), o.Total())
+			return err
+		}))
+
+		assert.False(t, containsBreach(t, sla, o))
+	})
+}
+
+func containsBreach(t *testing.T, sla *repository.SLARepository, o *order.Order) bool {
+	t.Helper()
+
+	breaches, err := sla.FindOpenBreaches(context.Background(), 1000)
+	require.NoError(t, err)
+
+	for _, breach := range breaches {
+		if breach.OrderID == o.ID() {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,55 @@
+// Package order creates order repository configured to store either orders state or orders events.
+package order
+
+import (
+	"fmt"
+	"github.com/go-feast/topics"
+	"gorm.io/gorm"
+	"service/config"
+	"service/domain/order"
+	"service/event"
+	"service/infrastructure/outbox"
+	"service/infrastructure/repositories/order/eventsourced"
+	repository "service/infrastructure/repositories/order/gorm"
+	"service/pubsub"
+)
+
+// Repository is used both by message handlers and by the outbox.
+type Repository interface {
+	outbox.Repository
+	order.OnceOperator
+}
+
+// NewOrderRepository creates order repository which stores either orders state or orders events.
+// Every order state transition is written into the outbox, see [pubsub.StatusChanged].
+func NewOrderRepository(db *gorm.DB, c *config.DBConfig) (Repository, error) {
+	err := pubsub.InitializeSQLTopics(db,
+		pubsub.StatusChanged.String(),
+		pubsub.Modified.String(),
+		topics.OrderCreated.String(),
+		pubsub.Canceled.String(),
+		pubsub.SLABreached.String(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := outbox.NewChangesWriter(event.JSONMarshaler{})
+
+	switch c.OrderStore {
+	case config.OrderStoreState:
+		return repository.NewOrderRepository(db,
+			repository.WithOperateRetries(c.OperateRetries),
+			repository.WithChangesWriter(changes),
+		), nil
+	case config.OrderStoreEvents:
+		repository.InitializeEventStoreScheme(db)
+
+		return eventsourced.NewRepository(repository.NewEventStore(db, repository.WithEventsWriter(changes)),
+			eventsourced.WithSnapshotEvery(c.SnapshotEvery),
+			eventsourced.WithOperateRetries(c.OperateRetries),
+		), nil
+	default:
+		return nil, fmt.Errorf("unknown order store: %s", c.OrderStore)
+	}
+}
@@ -0,0 +1,190 @@
+// Package geojson provides delivery zones described in GeoJSON.
+//
+// Every feature must have restaurant_id property. Polygon and MultiPolygon features add polygons to
+// the restaurant zone, Point feature with radius_km property sets restaurant location and zone radius.
+// Only outer rings of polygons are used.
+package geojson
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"io"
+	"os"
+	"service/domain/shared/destination"
+	"service/domain/shared/zone"
+)
+
+const (
+	TypePoint        = "Point"
+	TypePolygon      = "Polygon"
+	TypeMultiPolygon = "MultiPolygon"
+)
+
+var ErrUnsupportedGeometry = errors.New("unsupported geometry type")
+
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+type Feature struct {
+	Type       string     `json:"type"`
+	Geometry   Geometry   `json:"geometry"`
+	Properties Properties `json:"properties"`
+}
+
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+type Properties struct {
+	RestaurantID uuid.UUID `json:"restaurant_id"`
+	Radius       float64   `json:"radius_km"`
+}
+
+// position is a GeoJSON position: longitude goes before latitude.
+type position [2]float64
+
+func (p position) destination() (destination.Destination, error) {
+	return destination.NewDestination(p[1], p[0])
+}
+
+type Provider struct {
+	zones map[uuid.UUID]zone.Zone
+}
+
+// Load reads delivery zones from GeoJSON file.
+func Load(path string) (*Provider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "geojson: failed to open file")
+	}
+
+	defer file.Close()
+
+	return NewProvider(file)
+}
+
+// NewProvider reads delivery zones from GeoJSON FeatureCollection.
+func NewProvider(r io.Reader) (*Provider, error) {
+	collection := &FeatureCollection{}
+
+	if err := json.NewDecoder(r).Decode(collection); err != nil {
+		return nil, errors.Wrap(err, "geojson: failed to decode feature collection")
+	}
+
+	type draft struct {
+		center   destination.Destination
+		radius   float64
+		polygons []zone.Polygon
+	}
+
+	drafts := make(map[uuid.UUID]*draft)
+
+	for i, feature := range collection.Features {
+		id := feature.Properties.RestaurantID
+
+		d, ok := drafts[id]
+		if !ok {
+			d = &draft{}
+			drafts[id] = d
+		}
+
+		if feature.Geometry.Type == TypePoint {
+			var p position
+			if err := json.Unmarshal(feature.Geometry.Coordinates, &p); err != nil {
+				return nil, errors.Wrapf(err, "geojson: feature %d: invalid point", i)
+			}
+
+			center, err := p.destination()
+			if err != nil {
+				return nil, errors.Wrapf(err, "geojson: feature %d", i)
+			}
+
+			d.center, d.radius = center, feature.Properties.Radius
+
+			continue
+		}
+
+		polygons, err := ParsePolygons(feature.Geometry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "geojson: feature %d", i)
+		}
+
+		d.polygons = append(d.polygons, polygons...)
+	}
+
+	p := &Provider{zones: make(map[uuid.UUID]zone.Zone, len(drafts))}
+
+	for id, d := range drafts {
+		z, err := zone.NewZone(d.center, d.radius, d.polygons...)
+		if err != nil {
+			return nil, errors.Wrapf(err, "geojson: restaurant %s", id)
+		}
+
+		p.zones[id] = z
+	}
+
+	return p, nil
+}
+
+func (p *Provider) Zone(_ context.Context, restaurantID uuid.UUID) (zone.Zone, error) {
+	z, ok := p.zones[restaurantID]
+	if !ok {
+		return zone.Zone{}, errors.Wrapf(zone.ErrZoneNotFound, "geojson: restaurant %s", restaurantID)
+	}
+
+	return z, nil
+}
+
+// ParsePolygons parses Polygon or MultiPolygon geometry.
+func ParsePolygons(g Geometry) ([]zone.Polygon, error) {
+	var rings [][][]position
+
+	switch g.Type {
+	case TypePolygon:
+		var polygon [][]position
+		if err := json.Unmarshal(g.Coordinates, &polygon); err != nil {
+			return nil, errors.Wrap(err, "invalid polygon")
+		}
+
+		rings = [][][]position{polygon}
+	case TypeMultiPolygon:
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, errors.Wrap(err, "invalid multipolygon")
+		}
+	default:
+		return nil, errors.Wrapf(ErrUnsupportedGeometry, "%q", g.Type)
+	}
+
+	polygons := make([]zone.Polygon, 0, len(rings))
+
+	for _, polygon := range rings {
+		if len(polygon) == 0 {
+			return nil, zone.ErrInvalidPolygon
+		}
+
+		vertices := make([]destination.Destination, len(polygon[0]))
+
+		for i, p := range polygon[0] {
+			vertex, err := p.destination()
+			if err != nil {
+				return nil, err
+			}
+
+			vertices[i] = vertex
+		}
+
+		parsed, err := zone.NewPolygon(vertices...)
+		if err != nil {
+			return nil, err
+		}
+
+		polygons = append(polygons, parsed)
+	}
+
+	return polygons, nil
+}
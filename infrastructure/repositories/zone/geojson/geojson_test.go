@@ -0,0 +1,85 @@
+package geojson_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"service/domain/shared/destination"
+	"service/domain/shared/zone"
+	"service/infrastructure/repositories/zone/geojson"
+	"strings"
+	"testing"
+)
+
+const collection = `{
+  "type": "FeatureCollection",
+  "features": [
+    {
+      "type": "Feature",
+      "properties": {"restaurant_id": "6f1c2f7e-8d44-4a52-9c43-2c1f6a9b7d10"},
+      "geometry": {
+        "type": "Polygon",
+        "coordinates": [[[30.0, 50.0], [30.1, 50.0], [30.1, 50.1], [30.0, 50.1], [30.0, 50.0]]]
+      }
+    },
+    {
+      "type": "Feature",
+      "properties": {"restaurant_id": "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed", "radius_km": 3},
+      "geometry": {"type": "Point", "coordinates": [24.03, 49.84]}
+    }
+  ]
+}`
+
+func TestProvider_Zone(t *testing.T) {
+	provider, err := geojson.NewProvider(strings.NewReader(collection))
+	require.NoError(t, err)
+
+	testCases := []struct { //nolint:govet
+		name         string
+		restaurantID string
+		lat, long    float64
+		contains     bool
+	}{
+		{"inside polygon", "6f1c2f7e-8d44-4a52-9c43-2c1f6a9b7d10", 50.05, 30.05, true},
+		{"outside polygon", "6f1c2f7e-8d44-4a52-9c43-2c1f6a9b7d10", 50.05, 30.2, false},
+		{"inside radius", "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed", 49.85, 24.04, true},
+		{"outside radius", "1b9d6bcd-bbfd-4b2d-9b5d-ab8dfbbd4bed", 49.95, 24.04, false},
+	}
+
+	for _, testCase := range testCases {
+		tc := testCase
+		t.Run(tc.name, func(t *testing.T) {
+			z, err := provider.Zone(context.Background(), uuid.MustParse(tc.restaurantID))
+			require.NoError(t, err)
+
+			d, err := destination.NewDestination(tc.lat, tc.long)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.contains, z.Contains(d))
+		})
+	}
+
+	t.Run("assert unknown restaurant has no zone", func(t *testing.T) {
+		_, err := provider.Zone(context.Background(), uuid.New())
+		assert.ErrorIs(t, err, zone.ErrZoneNotFound)
+	})
+}
+
+func TestNewProvider_Invalid(t *testing.T) {
+	t.Run("assert unsupported geometry is rejected", func(t *testing.T) {
+		_, err := geojson.NewProvider(strings.NewReader(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "properties": {"restaurant_id": "6f1c2f7e-8d44-4a52-9c43-2c1f6a9b7d10"},
+			 "geometry": {"type": "LineString", "coordinates": [[30.0, 50.0], [30.1, 50.0]]}}]}`))
+
+		assert.ErrorIs(t, err, geojson.ErrUnsupportedGeometry)
+	})
+
+	t.Run("assert point without radius is rejected", func(t *testing.T) {
+		_, err := geojson.NewProvider(strings.NewReader(`{"type": "FeatureCollection", "features": [
+			{"type": "Feature", "properties": {"restaurant_id": "6f1c2f7e-8d44-4a52-9c43-2c1f6a9b7d10"},
+			 "geometry": {"type": "Point", "coordinates": [30.0, 50.0]}}]}`))
+
+		assert.ErrorIs(t, err, zone.ErrEmptyZone)
+	})
+}
@@ -0,0 +1,82 @@
+package gorm
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	"service/domain/shared/destination"
+	"service/domain/shared/zone"
+	"service/infrastructure/repositories/zone/geojson"
+)
+
+func InitializeZoneScheme(db *gorm.DB) {
+	err := db.AutoMigrate(&DeliveryZoneDTO{})
+	if err != nil {
+		panic(errors.Wrap(err, "failed to migrate database"))
+	}
+}
+
+// DeliveryZoneDTO represents restaurant delivery zone.
+// Geometry is an optional GeoJSON Polygon or MultiPolygon geometry.
+type DeliveryZoneDTO struct { //nolint:govet
+	RestaurantID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	Latitude     float64   `gorm:"type:numeric"`
+	Longitude    float64   `gorm:"type:numeric"`
+	Radius       float64   `gorm:"type:numeric"`
+	Geometry     []byte    `gorm:"type:jsonb"`
+}
+
+func (DeliveryZoneDTO) TableName() string { return "delivery_zones" }
+
+type ZoneRepository struct {
+	db *gorm.DB
+}
+
+func NewZoneRepository(db *gorm.DB) *ZoneRepository {
+	return &ZoneRepository{db: db}
+}
+
+func (r *ZoneRepository) Zone(ctx context.Context, restaurantID uuid.UUID) (zone.Zone, error) {
+	dto := &DeliveryZoneDTO{}
+
+	result := r.db.WithContext(ctx).Find(dto, "restaurant_id = ?", restaurantID)
+	if result.Error != nil {
+		return zone.Zone{}, errors.Wrap(result.Error, "gorm repository: zone get: failed to find zone")
+	}
+
+	if result.RowsAffected == 0 {
+		return zone.Zone{}, errors.Wrapf(zone.ErrZoneNotFound, "gorm repository: zone get: %s", restaurantID)
+	}
+
+	z, err := dto.ToZone()
+	if err != nil {
+		return zone.Zone{}, errors.Wrapf(err, "gorm repository: zone get: %s", restaurantID)
+	}
+
+	return z, nil
+}
+
+func (d *DeliveryZoneDTO) ToZone() (zone.Zone, error) {
+	center, err := destination.NewDestination(d.Latitude, d.Longitude)
+	if err != nil {
+		return zone.Zone{}, errors.WithMessage(err, "invalid zone center")
+	}
+
+	var polygons []zone.Polygon
+
+	if len(d.Geometry) != 0 {
+		g := geojson.Geometry{}
+		if err = json.Unmarshal(d.Geometry, &g); err != nil {
+			return zone.Zone{}, errors.Wrap(err, "invalid zone geometry")
+		}
+
+		polygons, err = geojson.ParsePolygons(g)
+		if err != nil {
+			return zone.Zone{}, errors.WithMessage(err, "invalid zone geometry")
+		}
+	}
+
+	return zone.NewZone(center, d.Radius, polygons...)
+}
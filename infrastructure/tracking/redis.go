@@ -0,0 +1,210 @@
+// Package tracking keeps locations of couriers delivering orders.
+package tracking
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/redis/go-redis/v9"
+	"service/domain/order"
+	"service/domain/shared/destination"
+	"time"
+)
+
+var _ order.LocationTracker = (*RedisTracker)(nil)
+
+const (
+	latestKeyPrefix        = "order:courier:location:"
+	locationsChannelPrefix = "order:courier:locations:"
+)
+
+// trackScript stores location ARGV[1] under KEYS[1] for ARGV[3] milliseconds, if positive, and publishes it to channel ARGV[4]
+// unless the stored location has been tracked after ARGV[2] microseconds.
+// Microseconds keep time precise in Lua numbers which are doubles.
+var trackScript = redis.NewScript(`
+local latest = redis.call('GET', KEYS[1])
+if latest then
+	local at = cjson.decode(latest).at_micro
+	if at and at > tonumber(ARGV[2]) then
+		return 0
+	end
+end
+
+if tonumber(ARGV[3]) > 0 then
+	redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[3])
+else
+	redis.call('SET', KEYS[1], ARGV[1])
+end
+redis.call('PUBLISH', ARGV[4], ARGV[1])
+
+return 1
+`)
+
+// locationDTO represents courier location stored in and published to Redis.
+type locationDTO struct { //nolint:govet
+	OrderID   uuid.UUID `json:"order_id"`
+	CourierID uuid.UUID `json:"courier_id"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	At        time.Time `json:"at"`
+
+	// AtMicro is At in unix microseconds locations are compared by in Redis, see trackScript.
+	AtMicro int64 `json:"at_micro"`
+}
+
+func toDTO(l order.CourierLocation) locationDTO {
+	return locationDTO{
+		OrderID:   l.OrderID,
+		CourierID: l.CourierID,
+		Latitude:  l.Position.Latitude(),
+		Longitude: l.Position.Longitude(),
+		At:        l.At,
+		AtMicro:   l.At.UnixMicro(),
+	}
+}
+
+func (d locationDTO) toLocation() (order.CourierLocation, error) {
+	position, err := destination.NewDestination(d.Latitude, d.Longitude)
+	if err != nil {
+		return order.CourierLocation{}, err
+	}
+
+	return order.CourierLocation{
+		OrderID:   d.OrderID,
+		CourierID: d.CourierID,
+		Position:  position,
+		At:        d.At,
+	}, nil
+}
+
+// RedisTracker keeps the latest courier location of an order under a key and relays locations with Redis pub/sub,
+// so subscribers get locations tracked by any service instance.
+type RedisTracker struct {
+	client redis.UniversalClient
+
+	// ttl is how long the latest location is kept after it is tracked.
+	ttl time.Duration
+}
+
+type Option func(*RedisTracker)
+
+// WithTTL sets how long the latest location is kept after it is tracked.
+func WithTTL(ttl time.Duration) Option {
+	return func(t *RedisTracker) {
+		t.ttl = ttl
+	}
+}
+
+func NewRedisTracker(client redis.UniversalClient, opts ...Option) *RedisTracker {
+	t := &RedisTracker{
+		client: client,
+		ttl:    time.Hour,
+	}
+
+	for _, opt := range opts {
+		opt(t)
+	}
+
+	return t
+}
+
+// Track stores and publishes l atomically. Location older than the stored one is ignored,
+// so location delivered late does not replace the latest one.
+func (t *RedisTracker) Track(ctx context.Context, l order.CourierLocation) error {
+	bytes, err := json.Marshal(toDTO(l))
+	if err != nil {
+		return errors.Wrap(err, "redis tracker: failed to encode location")
+	}
+
+	err = trackScript.Run(ctx, t.client, []string{latestKeyPrefix + l.OrderID.String()},
+		bytes, l.At.UnixMicro(), t.ttl.Milliseconds(), locationsChannelPrefix+l.OrderID.String(),
+	).Err()
+	if err != nil {
+		return errors.Wrap(err, "redis tracker: failed to track location")
+	}
+
+	return nil
+}
+
+func (t *RedisTracker) Latest(ctx context.Context, orderID uuid.UUID) (order.CourierLocation, error) {
+	bytes, err := t.client.Get(ctx, latestKeyPrefix+orderID.String()).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+		return order.CourierLocation{}, errors.Wrapf(order.ErrLocationNotFound, "redis tracker: %s", orderID)
+	case err != nil:
+		return order.CourierLocation{}, errors.Wrap(err, "redis tracker: failed to get location")
+	}
+
+	return decode(bytes)
+}
+
+func (t *RedisTracker) Subscribe(ctx context.Context, orderID uuid.UUID) (<-chan order.CourierLocation, error) {
+	pubsub := t.client.Subscribe(ctx, locationsChannelPrefix+orderID.String())
+
+	// subscription is confirmed, so every location tracked after Subscribe returns is received
+	if _, err := pubsub.Receive(ctx); err != nil {
+		_ = pubsub.Close()
+		return nil, errors.Wrap(err, "redis tracker: failed to subscribe")
+	}
+
+	locations := make(chan order.CourierLocation, 1)
+
+	go func() {
+		defer close(locations)
+		defer pubsub.Close()
+
+		messages := pubsub.Channel()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case m, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				l, err := decode([]byte(m.Payload))
+				if err != nil {
+					continue
+				}
+
+				replace(locations, l)
+			}
+		}
+	}()
+
+	return locations, nil
+}
+
+func decode(bytes []byte) (order.CourierLocation, error) {
+	var dto locationDTO
+	if err := json.Unmarshal(bytes, &dto); err != nil {
+		return order.CourierLocation{}, errors.Wrap(err, "redis tracker: failed to decode location")
+	}
+
+	l, err := dto.toLocation()
+	if err != nil {
+		return order.CourierLocation{}, errors.Wrap(err, "redis tracker: invalid location")
+	}
+
+	return l, nil
+}
+
+// replace sends l to locations replacing the location which has not been received yet.
+// Sending never blocks as locations has the only sender.
+func replace(locations chan order.CourierLocation, l order.CourierLocation) {
+	select {
+	case locations <- l:
+		return
+	default:
+	}
+
+	select {
+	case <-locations:
+	default:
+	}
+
+	locations <- l
+}
@@ -0,0 +1,113 @@
+package tracking_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"service/domain/order"
+	"service/domain/shared/destination"
+	"service/infrastructure/tracking"
+	"testing"
+	"time"
+)
+
+// openRedis connects to Redis provided by TEST_REDIS_URL. Test is skipped if it is not set.
+func openRedis(t *testing.T) *redis.Client {
+	t.Helper()
+
+	url := os.Getenv("TEST_REDIS_URL")
+	if url == "" {
+		t.Skip("TEST_REDIS_URL is not set")
+	}
+
+	options, err := redis.ParseURL(url)
+	require.NoError(t, err)
+
+	client := redis.NewClient(options)
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func location(t *testing.T, orderID uuid.UUID, lat, long float64) order.CourierLocation {
+	position, err := destination.NewDestination(lat, long)
+	require.NoError(t, err)
+
+	return order.CourierLocation{
+		OrderID:   orderID,
+		CourierID: uuid.New(),
+		Position:  position,
+		At:        time.Now().UTC().Truncate(time.Millisecond),
+	}
+}
+
+func TestRedisTracker(t *testing.T) {
+	tracker := tracking.NewRedisTracker(openRedis(t), tracking.WithTTL(time.Minute))
+	ctx := context.Background()
+
+	t.Run("assert latest location is kept", func(t *testing.T) {
+		orderID := uuid.New()
+
+		_, err := tracker.Latest(ctx, orderID)
+		assert.ErrorIs(t, err, order.ErrLocationNotFound)
+
+		require.NoError(t, tracker.Track(ctx, location(t, orderID, 50.45, 30.52)))
+
+		last := location(t, orderID, 50.46, 30.53)
+		require.NoError(t, tracker.Track(ctx, last))
+
+		latest, err := tracker.Latest(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, last.Position, latest.Position)
+		assert.True(t, last.At.Equal(latest.At))
+	})
+
+	t.Run("assert location older than the latest is ignored", func(t *testing.T) {
+		orderID := uuid.New()
+
+		latest := location(t, orderID, 50.46, 30.53)
+		require.NoError(t, tracker.Track(ctx, latest))
+
+		stale := location(t, orderID, 50.45, 30.52)
+		stale.At = latest.At.Add(-time.Second)
+		require.NoError(t, tracker.Track(ctx, stale))
+
+		got, err := tracker.Latest(ctx, orderID)
+		require.NoError(t, err)
+		assert.Equal(t, latest.Position, got.Position)
+		assert.True(t, latest.At.Equal(got.At))
+	})
+
+	t.Run("assert subscriber receives tracked locations of the order", func(t *testing.T) {
+		orderID := uuid.New()
+
+		subscriptionCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		locations, err := tracker.Subscribe(subscriptionCtx, orderID)
+		require.NoError(t, err)
+
+		require.NoError(t, tracker.Track(ctx, location(t, uuid.New(), 50.45, 30.52)))
+
+		tracked := location(t, orderID, 50.46, 30.53)
+		require.NoError(t, tracker.Track(ctx, tracked))
+
+		select {
+		case l := <-locations:
+			assert.Equal(t, orderID, l.OrderID)
+			assert.Equal(t, tracked.Position, l.Position)
+		case <-time.After(5 * time.Second):
+			t.Fatal("location is not received")
+		}
+
+		cancel()
+
+		assert.Eventually(t, func() bool {
+			_, ok := <-locations
+			return !ok
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
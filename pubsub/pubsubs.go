@@ -1,6 +1,7 @@
 package pubsub
 
 import (
+	stdsql "database/sql"
 	"errors"
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-kafka/v2/pkg/kafka"
@@ -41,6 +42,34 @@ func NewSQLPublisher(db *gorm.DB, logger watermill.LoggerAdapter) (message.Publi
 	return sql.NewPublisher(sqldb, sqlPublisherConfig, logger)
 }
 
+// NewSQLTxPublisher creates publisher that inserts messages within the ongoing gorm transaction tx,
+// so messages are published only if the transaction is committed.
+// Topics schema is not initialized by the publisher, see [InitializeSQLTopics].
+func NewSQLTxPublisher(tx *gorm.DB, logger watermill.LoggerAdapter) (message.Publisher, error) {
+	sqltx, ok := tx.Statement.ConnPool.(*stdsql.Tx)
+	if !ok {
+		return nil, errors.New("sql tx publisher: gorm db is not in transaction")
+	}
+
+	config := sqlPublisherConfig
+	config.AutoInitializeSchema = false
+
+	return sql.NewPublisher(sqltx, config, logger)
+}
+
+// InitializeSQLTopics creates tables of topics messages are published to by [NewSQLTxPublisher].
+func InitializeSQLTopics(db *gorm.DB, topics ...string) error {
+	for _, topic := range topics {
+		for _, query := range sqlPublisherConfig.SchemaAdapter.SchemaInitializingQueries(topic) {
+			if err := db.Exec(query).Error; err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
 func NewSQLSubscriber(db *gorm.DB, logger watermill.LoggerAdapter) (message.Subscriber, error) {
 	sqldb, err := db.DB()
 	if err != nil {
@@ -0,0 +1,39 @@
+package pubsub
+
+// Topic represents a message topic that is not provided by [github.com/go-feast/topics].
+type Topic string
+
+func (t Topic) String() string { return string(t) }
+
+const (
+	// Modified is a topic where order service reports modified order items.
+	Modified Topic = "order.modified"
+
+	// StatusChanged is a topic where order service reports every order state transition.
+	StatusChanged Topic = "order.status.changed"
+
+	// Canceled is a topic where order service reports orders it has canceled itself.
+	// Cancellation requests of other services are consumed from [github.com/go-feast/topics.Canceled].
+	Canceled Topic = "order.service.canceled"
+
+	// SLABreached is a topic where order service reports orders stuck in their state longer than allowed.
+	SLABreached Topic = "order.sla.breached"
+
+	// CourierReleased is a topic where courier service reports courier dropped the order.
+	CourierReleased Topic = "order.courier.released"
+
+	// CourierReassigned is a topic where courier service reports the order has been given to another courier.
+	CourierReassigned Topic = "order.courier.reassigned"
+
+	// ReadyForPickup is a topic where restaurant reports pickup order is ready to be picked up.
+	ReadyForPickup Topic = "order.pickup.ready"
+
+	// PickedUp is a topic where restaurant reports pickup order has been handed over to the customer.
+	PickedUp Topic = "order.picked.up"
+
+	// Refunded is a topic where payment provider reports successful refunds.
+	Refunded Topic = "order.refunded"
+
+	// RefundFailed is a topic where payment provider reports failed refunds.
+	RefundFailed Topic = "order.refund.failed"
+)
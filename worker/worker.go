@@ -0,0 +1,32 @@
+// Package worker runs periodic background jobs.
+package worker
+
+import (
+	"context"
+	"github.com/rs/zerolog"
+	"time"
+)
+
+// Job is a unit of periodic work.
+type Job func(ctx context.Context) error
+
+// Run runs job every interval until ctx is done.
+// Job errors are logged and do not stop Run.
+func Run(ctx context.Context, name string, interval time.Duration, job Job, logger *zerolog.Logger) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger.Info().Str("job", name).Dur("interval", interval).Msg("running job")
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info().Str("job", name).Msg("job stopped")
+			return
+		case <-ticker.C:
+			if err := job(ctx); err != nil {
+				logger.Err(err).Str("job", name).Msg("job failed")
+			}
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package worker_test
+
+import (
+	"context"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"service/logging"
+	"service/worker"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRun(t *testing.T) {
+	t.Run("assert job runs periodically until context is done", func(t *testing.T) {
+		var calls atomic.Int32
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+
+		go func() {
+			worker.Run(ctx, "test", time.Millisecond, func(context.Context) error {
+				calls.Add(1)
+				return errors.New("job error")
+			}, logging.NewNopLogger())
+
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return calls.Load() >= 2 }, time.Second, time.Millisecond)
+
+		cancel()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("worker has not stopped")
+		}
+	})
+}